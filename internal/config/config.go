@@ -0,0 +1,269 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the user's settings from config.yml. Fields missing from the file keep
+// their defaults.
+type Config struct {
+	// RefreshInterval is how often the conversation list is reloaded from chat.db.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	// TimeFormat is "12h" (3:04 PM) or "24h" (15:04).
+	TimeFormat string `yaml:"time_format"`
+	// DateLocale decides whether dates are written month first ("Jan 2") or day first
+	// ("2 Jan"), e.g. "en_US" or "en_GB". Empty uses $LC_ALL, $LC_TIME or $LANG.
+	DateLocale string `yaml:"date_locale"`
+	// DefaultView is the screen shown at startup: menu, conversations, contacts or
+	// unknown-senders.
+	DefaultView string `yaml:"default_view"`
+	// DefaultTab is the conversation tab shown first: all, known or unknown.
+	DefaultTab string `yaml:"default_tab"`
+	// Theme is auto, dark, light, high-contrast, mono or the name of a file in the themes
+	// directory. NO_COLOR forces mono.
+	Theme string `yaml:"theme"`
+	// DataDir holds contacts, groups, templates and the other files Chime writes.
+	DataDir string `yaml:"data_dir"`
+	// Region is the phone region for numbers without a country code, e.g. "GB".
+	// Empty uses the locale.
+	Region string `yaml:"region"`
+	// ContactsBackend is "yaml", "sqlite" or empty to pick automatically.
+	// CHIME_CONTACTS_BACKEND overrides it.
+	ContactsBackend string `yaml:"contacts_backend"`
+	// NameSources is the order names are looked up in. CHIME_NAME_SOURCES overrides it.
+	NameSources []string `yaml:"name_sources"`
+	Send        Send     `yaml:"send"`
+	// Keys rebinds actions, e.g. refresh: [r, f5]. An empty list unbinds the action.
+	Keys map[string][]string `yaml:"keys,omitempty"`
+}
+
+// Send holds the settings for sending messages.
+type Send struct {
+	// UndoDelay is how long a message waits as a pending bubble before it is sent.
+	// Zero sends immediately.
+	UndoDelay time.Duration `yaml:"undo_delay"`
+	// EnterSends sends with enter instead of ctrl+s; alt+enter then inserts a new line.
+	EnterSends bool `yaml:"enter_sends"`
+	// VerifyTimeout is how long to wait for a sent message to appear in chat.db.
+	VerifyTimeout time.Duration `yaml:"verify_timeout"`
+	// CharLimit is the longest message that can be typed.
+	CharLimit int `yaml:"char_limit"`
+}
+
+// Views and tabs accepted by DefaultView and DefaultTab.
+var (
+	Views = []string{"menu", "conversations", "contacts", "unknown-senders"}
+	Tabs  = []string{"all", "known", "unknown"}
+)
+
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}([_-][A-Za-z]{2})?$`)
+
+var (
+	current = Default()
+	mu      sync.RWMutex
+)
+
+// Default returns the settings used when there is no config file.
+func Default() Config {
+	homeDir, _ := os.UserHomeDir()
+	return Config{
+		RefreshInterval: 5 * time.Second,
+		TimeFormat:      "12h",
+		DefaultView:     "menu",
+		DefaultTab:      "all",
+		Theme:           "auto",
+		DataDir:         filepath.Join(homeDir, ".chime"),
+		NameSources:     []string{"local", "addressbook"},
+		Send: Send{
+			UndoDelay:     5 * time.Second,
+			VerifyTimeout: 15 * time.Second,
+			CharLimit:     1000,
+		},
+	}
+}
+
+// Current returns the settings in use.
+func Current() Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Set replaces the settings in use.
+func Set(cfg Config) {
+	mu.Lock()
+	current = cfg
+	mu.Unlock()
+}
+
+// DataDir returns the directory Chime keeps its files in (~/.chime by default).
+func DataDir() string {
+	return Current().DataDir
+}
+
+// Path returns the config file to read: $XDG_CONFIG_HOME/chime/config.yml if it exists,
+// otherwise ~/.chime/config.yml.
+func Path() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		path := filepath.Join(xdg, "chime", "config.yml")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".chime", "config.yml")
+}
+
+// Load reads and validates the config file, applying CHIME_CONTACTS_BACKEND and
+// CHIME_NAME_SOURCES on top. A missing file gives the defaults. Returns the settings and
+// the path read.
+func Load() (Config, string, error) {
+	path := Path()
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return cfg, path, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err == nil {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return cfg, path, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	if backend := os.Getenv("CHIME_CONTACTS_BACKEND"); backend != "" {
+		cfg.ContactsBackend = backend
+	}
+	if sources := os.Getenv("CHIME_NAME_SOURCES"); sources != "" {
+		cfg.NameSources = strings.Split(sources, ",")
+	}
+
+	cfg.DataDir = ExpandHome(cfg.DataDir)
+	if cfg.DateLocale == "" {
+		cfg.DateLocale = detectLocale()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, path, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, path, nil
+}
+
+// Validate checks the settings Config knows the allowed values for. The phone region,
+// contacts backend and name sources are checked when they are applied.
+func (c Config) Validate() error {
+	if c.RefreshInterval < time.Second {
+		return fmt.Errorf("refresh_interval must be at least 1s, got %s", c.RefreshInterval)
+	}
+	if c.TimeFormat != "12h" && c.TimeFormat != "24h" {
+		return fmt.Errorf("time_format must be 12h or 24h, got %q", c.TimeFormat)
+	}
+	if c.DateLocale != "" && !localePattern.MatchString(c.DateLocale) {
+		return fmt.Errorf("date_locale must look like en_US, got %q", c.DateLocale)
+	}
+	if !contains(Views, c.DefaultView) {
+		return fmt.Errorf("default_view must be one of %s, got %q", strings.Join(Views, ", "), c.DefaultView)
+	}
+	if !contains(Tabs, c.DefaultTab) {
+		return fmt.Errorf("default_tab must be one of %s, got %q", strings.Join(Tabs, ", "), c.DefaultTab)
+	}
+	if strings.TrimSpace(c.Theme) == "" {
+		return fmt.Errorf("theme cannot be empty")
+	}
+	if strings.TrimSpace(c.DataDir) == "" {
+		return fmt.Errorf("data_dir cannot be empty")
+	}
+	if c.Send.UndoDelay < 0 || c.Send.UndoDelay > time.Minute {
+		return fmt.Errorf("send.undo_delay must be between 0s and 1m, got %s", c.Send.UndoDelay)
+	}
+	if c.Send.VerifyTimeout < time.Second {
+		return fmt.Errorf("send.verify_timeout must be at least 1s, got %s", c.Send.VerifyTimeout)
+	}
+	if c.Send.CharLimit < 1 {
+		return fmt.Errorf("send.char_limit must be positive, got %d", c.Send.CharLimit)
+	}
+	return nil
+}
+
+// ClockLayout returns the time.Format layout for a time of day.
+func (c Config) ClockLayout() string {
+	if c.TimeFormat == "24h" {
+		return "15:04"
+	}
+	return "3:04 PM"
+}
+
+// ShortDateLayout returns the layout for a day and month, "Jan 2" or "2 Jan".
+func (c Config) ShortDateLayout() string {
+	if c.monthFirst() {
+		return "Jan 2"
+	}
+	return "2 Jan"
+}
+
+// LongDateLayout returns the layout for a full date, "Jan 2, 2006" or "2 Jan 2006".
+func (c Config) LongDateLayout() string {
+	if c.monthFirst() {
+		return "Jan 2, 2006"
+	}
+	return "2 Jan 2006"
+}
+
+// monthFirst reports whether the date locale writes the month before the day.
+func (c Config) monthFirst() bool {
+	_, country, found := strings.Cut(strings.ReplaceAll(c.DateLocale, "-", "_"), "_")
+	if !found {
+		return true
+	}
+	switch strings.ToUpper(country) {
+	case "US", "PH", "FM", "MH":
+		return true
+	}
+	return false
+}
+
+// Marshal returns the settings as config.yml would hold them.
+func (c Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func detectLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		locale := strings.SplitN(os.Getenv(key), ".", 2)[0]
+		if localePattern.MatchString(locale) {
+			return locale
+		}
+	}
+	return "en_US"
+}
+
+// ExpandHome replaces a leading ~ in path with the user's home directory.
+func ExpandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
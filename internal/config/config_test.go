@@ -0,0 +1,201 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setHome points the home directory and locale at a temporary setup for the rest of the test
+// and returns the home directory.
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("CHIME_CONTACTS_BACKEND", "")
+	t.Setenv("CHIME_NAME_SOURCES", "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "")
+	t.Setenv("LANG", "en_GB.UTF-8")
+	return home
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(*Config)
+		wantErr string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"24h clock and a locale", func(c *Config) { c.TimeFormat = "24h"; c.DateLocale = "fr-FR" }, ""},
+		{"no undo delay", func(c *Config) { c.Send.UndoDelay = 0 }, ""},
+		{"refresh too fast", func(c *Config) { c.RefreshInterval = 500 * time.Millisecond }, "refresh_interval"},
+		{"time format", func(c *Config) { c.TimeFormat = "12" }, "time_format"},
+		{"date locale", func(c *Config) { c.DateLocale = "english" }, "date_locale"},
+		{"default view", func(c *Config) { c.DefaultView = "inbox" }, "default_view"},
+		{"default tab", func(c *Config) { c.DefaultTab = "blocked" }, "default_tab"},
+		{"empty theme", func(c *Config) { c.Theme = " " }, "theme"},
+		{"empty data dir", func(c *Config) { c.DataDir = "" }, "data_dir"},
+		{"negative undo delay", func(c *Config) { c.Send.UndoDelay = -time.Second }, "send.undo_delay"},
+		{"long undo delay", func(c *Config) { c.Send.UndoDelay = 2 * time.Minute }, "send.undo_delay"},
+		{"verify timeout", func(c *Config) { c.Send.VerifyTimeout = 0 }, "send.verify_timeout"},
+		{"char limit", func(c *Config) { c.Send.CharLimit = 0 }, "send.char_limit"},
+	}
+
+	for _, tt := range tests {
+		cfg := Default()
+		tt.change(&cfg)
+		err := cfg.Validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: Validate() = %v, want nil", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: Validate() = %v, want an error mentioning %s", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	home := setHome(t)
+
+	cfg, path, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, ".chime", "config.yml"); path != want {
+		t.Errorf("Load() path = %q, want %q", path, want)
+	}
+	want := Default()
+	want.DateLocale = "en_GB"
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() =\n%#v\nwant the defaults\n%#v", cfg, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	home := setHome(t)
+	xdg := filepath.Join(home, "xdg")
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	// Without a file under $XDG_CONFIG_HOME, ~/.chime/config.yml is read.
+	homeConfig := filepath.Join(home, ".chime", "config.yml")
+	writeConfig(t, homeConfig, "time_format: 24h\n")
+	cfg, path, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != homeConfig || cfg.TimeFormat != "24h" {
+		t.Errorf("Load() = time_format %q from %q, want 24h from %q", cfg.TimeFormat, path, homeConfig)
+	}
+
+	xdgConfig := filepath.Join(xdg, "chime", "config.yml")
+	writeConfig(t, xdgConfig, "date_locale: en_US\n"+
+		"data_dir: ~/chime-data\n"+
+		"name_sources: [addressbook]\n"+
+		"send:\n  undo_delay: 0s\n")
+	t.Setenv("CHIME_NAME_SOURCES", "local,addressbook")
+
+	cfg, path, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != xdgConfig {
+		t.Errorf("Load() path = %q, want %q", path, xdgConfig)
+	}
+	want := Default()
+	want.DateLocale = "en_US"
+	want.DataDir = filepath.Join(home, "chime-data")
+	want.NameSources = []string{"local", "addressbook"}
+	want.Send.UndoDelay = 0
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() =\n%#v\nwant\n%#v", cfg, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "time_format: 24h\ncolour: blue\n", "field colour not found"},
+		{"unknown nested key", "send:\n  delay: 5s\n", "field delay not found"},
+		{"wrong type", "refresh_interval: often\n", "cannot unmarshal"},
+		{"invalid value", "default_view: inbox\n", "invalid config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := setHome(t)
+			writeConfig(t, filepath.Join(home, ".chime", "config.yml"), tt.content)
+
+			_, _, err := Load()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDateLayouts(t *testing.T) {
+	tests := []struct {
+		timeFormat string
+		locale     string
+		clock      string
+		short      string
+		long       string
+	}{
+		{"12h", "en_US", "3:04 PM", "Jan 2", "Jan 2, 2006"},
+		{"24h", "en_GB", "15:04", "2 Jan", "2 Jan 2006"},
+		{"24h", "fr-FR", "15:04", "2 Jan", "2 Jan 2006"},
+		{"12h", "en_ph", "3:04 PM", "Jan 2", "Jan 2, 2006"},
+		{"12h", "en", "3:04 PM", "Jan 2", "Jan 2, 2006"},
+	}
+
+	for _, tt := range tests {
+		cfg := Config{TimeFormat: tt.timeFormat, DateLocale: tt.locale}
+		if got := cfg.ClockLayout(); got != tt.clock {
+			t.Errorf("%s: ClockLayout() = %q, want %q", tt.timeFormat, got, tt.clock)
+		}
+		if got := cfg.ShortDateLayout(); got != tt.short {
+			t.Errorf("%s: ShortDateLayout() = %q, want %q", tt.locale, got, tt.short)
+		}
+		if got := cfg.LongDateLayout(); got != tt.long {
+			t.Errorf("%s: LongDateLayout() = %q, want %q", tt.locale, got, tt.long)
+		}
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home := setHome(t)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", home},
+		{"~/contacts.vcf", filepath.Join(home, "contacts.vcf")},
+		{"~other/contacts.vcf", "~other/contacts.vcf"},
+		{"/tmp/~/contacts.vcf", "/tmp/~/contacts.vcf"},
+		{"contacts.vcf", "contacts.vcf"},
+	}
+	for _, tt := range tests {
+		if got := ExpandHome(tt.path); got != tt.want {
+			t.Errorf("ExpandHome(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
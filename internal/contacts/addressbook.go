@@ -0,0 +1,256 @@
+package contacts
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var addressBookVersions = []string{
+	"AddressBook-v22.abcddb",
+	"AddressBook-v23.abcddb",
+	"AddressBook-v24.abcddb",
+}
+
+var (
+	addressBookLookupMap  map[string]string
+	addressBookCacheMutex sync.RWMutex
+	addressBookCacheTime  time.Time
+	addressBookCacheTTL   = 5 * time.Minute
+)
+
+// GetAddressBookDir returns the macOS AddressBook directory (~/Library/Application Support/AddressBook).
+func GetAddressBookDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "Library", "Application Support", "AddressBook")
+}
+
+// GetAddressBookDBPath returns the path to the top-level AddressBook database,
+// falling back to the v22 file name if none of the known versions exist.
+func GetAddressBookDBPath() string {
+	baseDir := GetAddressBookDir()
+
+	for _, version := range addressBookVersions {
+		path := filepath.Join(baseDir, version)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return filepath.Join(baseDir, "AddressBook-v22.abcddb")
+}
+
+// GetAddressBookDBPaths returns every AddressBook database that exists on disk.
+// Synced accounts (iCloud, Google, Exchange) each keep their own database under Sources/,
+// and the top-level database is often empty when they are in use.
+func GetAddressBookDBPaths() []string {
+	baseDir := GetAddressBookDir()
+	var paths []string
+
+	for _, version := range addressBookVersions {
+		candidates := []string{filepath.Join(baseDir, version)}
+		sources, _ := filepath.Glob(filepath.Join(baseDir, "Sources", "*", version))
+		candidates = append(candidates, sources...)
+
+		for _, path := range candidates {
+			if _, err := os.Stat(path); err == nil {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	return paths
+}
+
+// ReadAddressBook reads every person with a name and at least one phone number or email
+// from the AddressBook SQLite database at path. The database is opened read-only.
+func ReadAddressBook(path string) ([]Contact, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open AddressBook database: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+		SELECT
+			Z_PK,
+			COALESCE(ZFIRSTNAME, ''),
+			COALESCE(ZLASTNAME, ''),
+			COALESCE(ZORGANIZATION, '')
+		FROM ZABCDRECORD
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AddressBook records: %w", err)
+	}
+	defer rows.Close()
+
+	records := make(map[int64]*Contact)
+	var order []int64
+
+	for rows.Next() {
+		var pk int64
+		var firstName, lastName, organization string
+		if err := rows.Scan(&pk, &firstName, &lastName, &organization); err != nil {
+			continue
+		}
+
+		name := strings.TrimSpace(strings.TrimSpace(firstName) + " " + strings.TrimSpace(lastName))
+		if name == "" {
+			name = strings.TrimSpace(organization)
+		}
+		if name == "" {
+			continue
+		}
+
+		contact := &Contact{Name: name}
+		if name != strings.TrimSpace(organization) {
+			contact.Organization = strings.TrimSpace(organization)
+		}
+		records[pk] = contact
+		order = append(order, pk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read AddressBook records: %w", err)
+	}
+
+	phoneRows, err := db.Query(`
+		SELECT ZOWNER, ZFULLNUMBER, COALESCE(ZLABEL, '')
+		FROM ZABCDPHONENUMBER
+		WHERE ZOWNER IS NOT NULL AND ZFULLNUMBER IS NOT NULL
+		ORDER BY ZOWNER, Z_PK
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AddressBook phone numbers: %w", err)
+	}
+	defer phoneRows.Close()
+
+	for phoneRows.Next() {
+		var owner int64
+		var number, label string
+		if err := phoneRows.Scan(&owner, &number, &label); err != nil {
+			continue
+		}
+		if contact, ok := records[owner]; ok && strings.TrimSpace(number) != "" {
+			contact.PhoneNumbers = append(contact.PhoneNumbers, strings.TrimSpace(number))
+			setLabel(contact, strings.TrimSpace(number), addressBookLabel(label))
+		}
+	}
+
+	emailRows, err := db.Query(`
+		SELECT ZOWNER, ZADDRESS, COALESCE(ZLABEL, '')
+		FROM ZABCDEMAILADDRESS
+		WHERE ZOWNER IS NOT NULL AND ZADDRESS IS NOT NULL
+		ORDER BY ZOWNER, Z_PK
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AddressBook email addresses: %w", err)
+	}
+	defer emailRows.Close()
+
+	for emailRows.Next() {
+		var owner int64
+		var address, label string
+		if err := emailRows.Scan(&owner, &address, &label); err != nil {
+			continue
+		}
+		if contact, ok := records[owner]; ok && strings.TrimSpace(address) != "" {
+			contact.Emails = append(contact.Emails, strings.TrimSpace(address))
+			setLabel(contact, strings.TrimSpace(address), addressBookLabel(label))
+		}
+	}
+
+	var contacts []Contact
+	for _, pk := range order {
+		contact := records[pk]
+		if len(contact.PhoneNumbers) == 0 && len(contact.Emails) == 0 {
+			continue
+		}
+		contacts = append(contacts, *contact)
+	}
+
+	return contacts, nil
+}
+
+// addressBookLabel converts an AddressBook label such as "_$!<Mobile>!$_" to a contact label.
+// Custom labels are stored as plain text and returned lowercased.
+func addressBookLabel(label string) string {
+	label = strings.TrimSuffix(strings.TrimPrefix(label, "_$!<"), ">!$_")
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label == "iphone" {
+		return "mobile"
+	}
+	return label
+}
+
+// ReadAllAddressBooks reads every AddressBook database returned by GetAddressBookDBPaths.
+// Databases that cannot be read are skipped.
+func ReadAllAddressBooks() []Contact {
+	var all []Contact
+	for _, path := range GetAddressBookDBPaths() {
+		contacts, err := ReadAddressBook(path)
+		if err != nil {
+			continue
+		}
+		all = append(all, contacts...)
+	}
+	return all
+}
+
+// FindAddressBookName looks up a phone number or email in the macOS AddressBook databases.
+// Returns the person's name if found, empty string otherwise.
+// The lookup map is built on first use and refreshed every 5 minutes.
+func FindAddressBookName(identifier string) string {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return ""
+	}
+
+	addressBookCacheMutex.RLock()
+	fresh := addressBookLookupMap != nil && time.Since(addressBookCacheTime) < addressBookCacheTTL
+	if fresh {
+		defer addressBookCacheMutex.RUnlock()
+		return addressBookLookupMap[NormalizeIdentifier(identifier)]
+	}
+	addressBookCacheMutex.RUnlock()
+
+	addressBookCacheMutex.Lock()
+	defer addressBookCacheMutex.Unlock()
+
+	if addressBookLookupMap == nil || time.Since(addressBookCacheTime) >= addressBookCacheTTL {
+		lookupMap := make(map[string]string)
+		for _, contact := range ReadAllAddressBooks() {
+			for _, phone := range contact.PhoneNumbers {
+				lookupMap[NormalizeIdentifier(phone)] = contact.Name
+			}
+			for _, email := range contact.Emails {
+				lookupMap[NormalizeIdentifier(email)] = contact.Name
+			}
+		}
+		if !maps.Equal(lookupMap, addressBookLookupMap) {
+			cacheGeneration.Add(1)
+		}
+		addressBookLookupMap = lookupMap
+		addressBookCacheTime = time.Now()
+	}
+
+	return addressBookLookupMap[NormalizeIdentifier(identifier)]
+}
+
+// ImportAddressBook copies people from the macOS AddressBook databases into ~/.chime/contacts/.
+// People whose name or any phone number or email already belongs to a local contact are skipped.
+// Returns the number of contacts imported and skipped.
+func ImportAddressBook() (int, int, error) {
+	paths := GetAddressBookDBPaths()
+	if len(paths) == 0 {
+		return 0, 0, fmt.Errorf("no AddressBook database found in %s", GetAddressBookDir())
+	}
+
+	return ImportContacts(ReadAllAddressBooks())
+}
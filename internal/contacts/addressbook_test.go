@@ -0,0 +1,133 @@
+package contacts
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeAddressBookFixture builds a minimal AddressBook database with the tables and columns
+// ReadAddressBook queries.
+func writeAddressBookFixture(t *testing.T, path string) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		`CREATE TABLE ZABCDRECORD (Z_PK INTEGER PRIMARY KEY, ZFIRSTNAME TEXT, ZLASTNAME TEXT, ZORGANIZATION TEXT)`,
+		`CREATE TABLE ZABCDPHONENUMBER (Z_PK INTEGER PRIMARY KEY, ZOWNER INTEGER, ZFULLNUMBER TEXT, ZLABEL TEXT)`,
+		`CREATE TABLE ZABCDEMAILADDRESS (Z_PK INTEGER PRIMARY KEY, ZOWNER INTEGER, ZADDRESS TEXT, ZLABEL TEXT)`,
+
+		`INSERT INTO ZABCDRECORD VALUES (1, 'Alice', 'Martin', NULL)`,
+		`INSERT INTO ZABCDRECORD VALUES (2, NULL, NULL, 'Acme Plumbing')`,
+		`INSERT INTO ZABCDRECORD VALUES (3, ' Bob ', '', 'Initech')`,
+		`INSERT INTO ZABCDRECORD VALUES (4, 'No', 'Handles', NULL)`,
+		`INSERT INTO ZABCDRECORD VALUES (5, NULL, NULL, NULL)`,
+
+		`INSERT INTO ZABCDPHONENUMBER VALUES (1, 1, '+44 7700 900123', '_$!<Mobile>!$_')`,
+		`INSERT INTO ZABCDPHONENUMBER VALUES (2, 1, '020 7946 0018', '_$!<Home>!$_')`,
+		`INSERT INTO ZABCDPHONENUMBER VALUES (3, 2, '(415) 555-0100', 'Office line')`,
+		`INSERT INTO ZABCDPHONENUMBER VALUES (4, 5, '+15555550199', NULL)`,
+		`INSERT INTO ZABCDPHONENUMBER VALUES (5, 3, NULL, NULL)`,
+		`INSERT INTO ZABCDPHONENUMBER VALUES (6, NULL, '+15555550100', NULL)`,
+
+		`INSERT INTO ZABCDEMAILADDRESS VALUES (1, 1, 'Alice@Example.com', '_$!<Work>!$_')`,
+		`INSERT INTO ZABCDEMAILADDRESS VALUES (2, 3, 'bob@initech.example', '_$!<iPhone>!$_')`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+}
+
+func TestReadAddressBook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "AddressBook-v22.abcddb")
+	writeAddressBookFixture(t, path)
+
+	got, err := ReadAddressBook(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Contact{
+		{
+			Name:         "Alice Martin",
+			PhoneNumbers: []string{"+44 7700 900123", "020 7946 0018"},
+			Emails:       []string{"Alice@Example.com"},
+			Labels: map[string]string{
+				"+44 7700 900123":   "mobile",
+				"020 7946 0018":     "home",
+				"Alice@Example.com": "work",
+			},
+		},
+		{
+			Name:         "Acme Plumbing",
+			PhoneNumbers: []string{"(415) 555-0100"},
+			Labels:       map[string]string{"(415) 555-0100": "office line"},
+		},
+		{
+			Name:         "Bob",
+			Organization: "Initech",
+			Emails:       []string{"bob@initech.example"},
+			Labels:       map[string]string{"bob@initech.example": "mobile"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAddressBook() =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestReadAddressBookMissingTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.abcddb")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE unrelated (id INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if _, err := ReadAddressBook(path); err == nil {
+		t.Error("ReadAddressBook() on a database without AddressBook tables succeeded")
+	}
+}
+
+func TestFindAddressBookName(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	setRegionForTest(t, "GB")
+
+	dir := filepath.Join(home, "Library", "Application Support", "AddressBook", "Sources", "ABC")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeAddressBookFixture(t, filepath.Join(dir, "AddressBook-v22.abcddb"))
+
+	tests := []struct {
+		handle string
+		want   string
+	}{
+		{"+447700900123", "Alice Martin"},
+		{"07700 900123", "Alice Martin"},
+		{"+442079460018", "Alice Martin"},
+		{"alice@example.com", "Alice Martin"},
+		{"BOB@initech.example", "Bob"},
+		{"+15555550199", ""},
+		{"+15555550100", ""},
+		{"nobody@example.com", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := FindAddressBookName(tt.handle); got != tt.want {
+			t.Errorf("FindAddressBookName(%q) = %q, want %q", tt.handle, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package contacts
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Blocklist holds the handles and patterns whose chats are hidden. An entry is a phone number
+// or email, matched after normalisation, a chat identifier, or a pattern using "*" and "?"
+// wildcards such as "+1900*" or "*@spam.example".
+type Blocklist struct {
+	Entries []string
+}
+
+// GetBlocklistPath returns the file listing blocked handles and patterns (~/.chime/blocklist.yml).
+func GetBlocklistPath() string {
+	return filepath.Join(filepath.Dir(GetContactsDir()), "blocklist.yml")
+}
+
+// GetMutedChatsPath returns the file listing muted chats (~/.chime/muted_chats.yml).
+func GetMutedChatsPath() string {
+	return filepath.Join(filepath.Dir(GetContactsDir()), "muted_chats.yml")
+}
+
+// LoadBlocklist reads the blocklist. A missing file is an empty blocklist.
+func LoadBlocklist() (Blocklist, error) {
+	entries, err := readStringList(GetBlocklistPath())
+	if err != nil {
+		return Blocklist{}, fmt.Errorf("failed to read blocklist: %w", err)
+	}
+	return Blocklist{Entries: entries}, nil
+}
+
+// Blocks reports whether a phone number or email matches an entry.
+func (b Blocklist) Blocks(handle string) bool {
+	return b.match(handle, true) != ""
+}
+
+// BlocksChat reports whether a chat is blocked: its identifier matches an entry or every one
+// of its participants is blocked.
+func (b Blocklist) BlocksChat(chatID string, participants []string) bool {
+	if len(b.Entries) == 0 {
+		return false
+	}
+	if b.match(chatID, false) != "" {
+		return true
+	}
+	if len(participants) == 0 {
+		return false
+	}
+	for _, participant := range participants {
+		if !b.Blocks(participant) {
+			return false
+		}
+	}
+	return true
+}
+
+// match returns the first entry matching handle, or "". Handles are compared after
+// normalisation when normalize is set, and literally otherwise.
+func (b Blocklist) match(handle string, normalize bool) string {
+	handle = strings.TrimSpace(handle)
+	if handle == "" {
+		return ""
+	}
+	normalized := ""
+	if normalize {
+		normalized = NormalizeIdentifier(handle)
+	}
+
+	for _, entry := range b.Entries {
+		if strings.ContainsAny(entry, "*?[") {
+			pattern := strings.ToLower(entry)
+			if ok, _ := path.Match(pattern, strings.ToLower(handle)); ok {
+				return entry
+			}
+			if ok, _ := path.Match(pattern, normalized); ok && normalized != "" {
+				return entry
+			}
+			continue
+		}
+		if strings.EqualFold(entry, handle) {
+			return entry
+		}
+		if normalized != "" && !isChatIdentifier(entry) && NormalizeIdentifier(entry) == normalized {
+			return entry
+		}
+	}
+	return ""
+}
+
+// Block adds handles or patterns to the blocklist.
+func Block(entries ...string) error {
+	blocklist, err := LoadBlocklist()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.ContainsAny(entry, "*?[") {
+			if _, err := path.Match(entry, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", entry, err)
+			}
+		}
+		if !containsFold(blocklist.Entries, entry) {
+			blocklist.Entries = append(blocklist.Entries, entry)
+		}
+	}
+
+	return writeStringList(GetBlocklistPath(), blocklist.Entries)
+}
+
+// Unblock removes the entries that block handle, or that equal it when handle is itself a
+// pattern. Returns the entries removed.
+func Unblock(handle string) ([]string, error) {
+	blocklist, err := LoadBlocklist()
+	if err != nil {
+		return nil, err
+	}
+
+	var kept, removed []string
+	for _, entry := range blocklist.Entries {
+		single := Blocklist{Entries: []string{entry}}
+		if strings.EqualFold(entry, strings.TrimSpace(handle)) || single.match(handle, !isChatIdentifier(handle)) != "" {
+			removed = append(removed, entry)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	return removed, writeStringList(GetBlocklistPath(), kept)
+}
+
+// ListMutedChats returns the chat identifiers of muted chats.
+func ListMutedChats() (map[string]bool, error) {
+	chats, err := readStringList(GetMutedChatsPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read muted chats: %w", err)
+	}
+
+	muted := make(map[string]bool, len(chats))
+	for _, chatID := range chats {
+		muted[chatID] = true
+	}
+	return muted, nil
+}
+
+// SetChatMuted mutes or unmutes the chat with the given chat identifier.
+func SetChatMuted(chatID string, mute bool) error {
+	muted, err := ListMutedChats()
+	if err != nil {
+		return err
+	}
+
+	if mute {
+		muted[chatID] = true
+	} else {
+		delete(muted, chatID)
+	}
+
+	list := make([]string, 0, len(muted))
+	for id := range muted {
+		list = append(list, id)
+	}
+	return writeStringList(GetMutedChatsPath(), list)
+}
+
+// isChatIdentifier reports whether s is a group chat identifier ("chat123...") rather than a
+// phone number or email, so it is never normalised as a phone number.
+func isChatIdentifier(s string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(s)), "chat")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// readStringList reads a YAML list of strings. A missing file is an empty list.
+func readStringList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []string
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// writeStringList writes list, sorted, as a YAML list of strings.
+func writeStringList(path string, list []string) error {
+	sort.Strings(list)
+	data, err := yaml.Marshal(list)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create chime directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
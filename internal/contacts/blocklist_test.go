@@ -0,0 +1,129 @@
+package contacts
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/saravenpi/chime/internal/config"
+)
+
+func TestBlocklistMatch(t *testing.T) {
+	setRegionForTest(t, "GB")
+
+	blocklist := Blocklist{Entries: []string{
+		"+1900*",
+		"*@spam.example",
+		"+4477009004??",
+		"07700 900123",
+		"Bob@Example.com",
+		"chat123456",
+	}}
+
+	tests := []struct {
+		handle    string
+		normalize bool
+		want      string
+	}{
+		{"+19005550100", true, "+1900*"},
+		{"+1 900 555 0100", true, "+1900*"},
+		{"eve@SPAM.example", true, "*@spam.example"},
+		{"eve@spam.example.org", true, ""},
+		{"07700 900456", true, "+4477009004??"},
+		{"+44 7700 900123", true, "07700 900123"},
+		{"+447700900123", true, "07700 900123"},
+		{"+447700900123", false, ""},
+		{"bob@example.com", true, "Bob@Example.com"},
+		{"chat123456", false, "chat123456"},
+		{"CHAT123456", false, "chat123456"},
+		{"123456", true, ""},
+		{"+447700900999", true, ""},
+		{"  ", true, ""},
+	}
+	for _, tt := range tests {
+		if got := blocklist.match(tt.handle, tt.normalize); got != tt.want {
+			t.Errorf("match(%q, %v) = %q, want %q", tt.handle, tt.normalize, got, tt.want)
+		}
+	}
+}
+
+func TestBlocksChat(t *testing.T) {
+	setRegionForTest(t, "GB")
+
+	blocklist := Blocklist{Entries: []string{"07700 900123", "+1900*", "chat123456", "999"}}
+
+	tests := []struct {
+		name         string
+		blocklist    Blocklist
+		chatID       string
+		participants []string
+		want         bool
+	}{
+		{"blocked chat identifier", blocklist, "chat123456", []string{"+15555550100", "+15555550101"}, true},
+		{"chat identifier in another case", blocklist, "CHAT123456", nil, true},
+		{"chat identifier isn't normalised", blocklist, "chat999", nil, false},
+		{"one-to-one with a blocked handle", blocklist, "+447700900123", []string{"+447700900123"}, true},
+		{"one-to-one with another handle", blocklist, "+447700900999", []string{"+447700900999"}, false},
+		{"group with every participant blocked", blocklist, "chat42", []string{"+44 7700 900123", "+19005550100"}, true},
+		{"group with some participants blocked", blocklist, "chat42", []string{"+447700900123", "+15555550100"}, false},
+		{"group without participants", blocklist, "chat42", nil, false},
+		{"empty blocklist", Blocklist{}, "chat123456", []string{"+447700900123"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.blocklist.BlocksChat(tt.chatID, tt.participants); got != tt.want {
+			t.Errorf("%s: BlocksChat(%q, %q) = %v, want %v", tt.name, tt.chatID, tt.participants, got, tt.want)
+		}
+	}
+}
+
+func TestUnblock(t *testing.T) {
+	previous := config.Current()
+	t.Cleanup(func() { config.Set(previous) })
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	config.Set(cfg)
+	setRegionForTest(t, "GB")
+
+	if err := Block("+1900*", "07700 900123", "*@spam.example", "chat123456", "alice@example.com", " "); err != nil {
+		t.Fatal(err)
+	}
+	if err := Block("[a-"); err == nil {
+		t.Error(`Block("[a-") succeeded, want an invalid pattern error`)
+	}
+
+	tests := []struct {
+		handle string
+		want   []string
+	}{
+		{"+44 7700 900123", []string{"07700 900123"}},
+		{"+19005550100", []string{"+1900*"}},
+		{"*@SPAM.example", []string{"*@spam.example"}},
+		{"chat123456", []string{"chat123456"}},
+		{"nobody@example.com", nil},
+		{"+447700900123", nil},
+	}
+	for _, tt := range tests {
+		removed, err := Unblock(tt.handle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(removed, tt.want) {
+			t.Errorf("Unblock(%q) = %q, want %q", tt.handle, removed, tt.want)
+		}
+	}
+
+	blocklist, err := LoadBlocklist()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"alice@example.com"}; !reflect.DeepEqual(blocklist.Entries, want) {
+		t.Errorf("blocklist after unblocking = %q, want %q", blocklist.Entries, want)
+	}
+
+	if err := os.WriteFile(GetBlocklistPath(), []byte("not: [a list"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBlocklist(); err == nil {
+		t.Error("LoadBlocklist() of a malformed file succeeded, want an error")
+	}
+}
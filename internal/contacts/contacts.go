@@ -2,75 +2,125 @@ package contacts
 
 import (
 	"fmt"
-	"os"
+	"maps"
+	"net/mail"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/google/uuid"
+	"github.com/saravenpi/chime/internal/config"
 )
 
 type Contact struct {
+	ID           string   `yaml:"id"`
 	Name         string   `yaml:"name"`
+	Nickname     string   `yaml:"nickname,omitempty"`
+	Organization string   `yaml:"organization,omitempty"`
+	Birthday     string   `yaml:"birthday,omitempty"`
 	PhoneNumbers []string `yaml:"phone_numbers,omitempty"`
 	Emails       []string `yaml:"emails,omitempty"`
+	// Labels maps a phone number or email, as written in PhoneNumbers or Emails, to a label
+	// such as "mobile", "home" or "work".
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// Normalized maps each phone number and email, as written, to the canonical form used to
+	// match it against chat.db handles (E.164 for numbers, lowercase for emails).
+	Normalized map[string]string `yaml:"normalized,omitempty"`
+	Tags       []string          `yaml:"tags,omitempty"`
+	Favorite   bool              `yaml:"favorite,omitempty"`
+	Notes      string            `yaml:"notes,omitempty"`
 }
 
+// DisplayName returns the name shown in conversations: the nickname if set, otherwise the name.
+func (c Contact) DisplayName() string {
+	if strings.TrimSpace(c.Nickname) != "" {
+		return strings.TrimSpace(c.Nickname)
+	}
+	return c.Name
+}
+
+// Label returns the label for a phone number or email, or "" if it has none.
+func (c Contact) Label(identifier string) string {
+	return c.Labels[identifier]
+}
+
+// HasTag reports whether the contact has tag, ignoring case.
+func (c Contact) HasTag(tag string) bool {
+	for _, t := range c.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// StandardLabels are the labels offered for phone numbers and emails. Any other label is kept as written.
+var StandardLabels = []string{"mobile", "home", "work", "other"}
+
 var (
 	contactCache      []Contact
 	contactLookupMap  map[string]string
 	contactCacheMutex sync.RWMutex
 	contactCacheTime  time.Time
 	cacheDuration     = 30 * time.Second
+
+	// cacheGeneration is bumped when the contact cache is invalidated or a reload finds that
+	// the names handles resolve to have changed.
+	cacheGeneration atomic.Uint64
 )
 
-// GetContactsDir returns the path to the contacts directory (~/.chime/contacts).
+// GetContactsDir returns the path to the contacts directory (~/.chime/contacts, or
+// "contacts" in the configured data directory).
 func GetContactsDir() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".chime", "contacts")
+	return filepath.Join(config.DataDir(), "contacts")
 }
 
-// ensureContactsDir creates the contacts directory if it doesn't exist.
-func ensureContactsDir() error {
-	dir := GetContactsDir()
-	return os.MkdirAll(dir, 0755)
+// NewContactID returns a new random contact ID.
+func NewContactID() string {
+	return uuid.NewString()
 }
 
-// sanitizeFilename converts a contact name to a safe filename.
-func sanitizeFilename(name string) string {
-	name = strings.TrimSpace(name)
-	name = strings.ReplaceAll(name, "/", "-")
-	name = strings.ReplaceAll(name, "\\", "-")
-	name = strings.ReplaceAll(name, ":", "-")
-	return name
+// isValidContactID reports whether id is a UUID, and therefore safe to use as a file name.
+func isValidContactID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
 }
 
-// getContactFilePath returns the full path to a contact's YAML file.
-func getContactFilePath(name string) string {
-	filename := sanitizeFilename(name) + ".yml"
-	return filepath.Join(GetContactsDir(), filename)
+// SaveContact saves a contact to the active store (by default ~/.chime/contacts/<id>.yml).
+// A contact without an ID is given a new one, so saving it creates a new contact.
+// Phone numbers and emails are trimmed, repeats are dropped and each one's canonical form is
+// stored in Normalized. Returns a *ValidationError if any is malformed or already belongs to
+// another contact.
+func SaveContact(contact Contact) error {
+	return saveContact(contact, saveOptions{})
 }
 
-// SaveContact saves a contact to a YAML file in ~/.chime/contacts/.
-func SaveContact(contact Contact) error {
-	if contact.Name == "" {
+func saveContact(contact Contact, opts saveOptions) error {
+	if strings.TrimSpace(contact.Name) == "" {
 		return fmt.Errorf("contact name cannot be empty")
 	}
 
-	if err := ensureContactsDir(); err != nil {
-		return fmt.Errorf("failed to create contacts directory: %w", err)
+	if contact.ID == "" {
+		contact.ID = NewContactID()
+	}
+	if !isValidContactID(contact.ID) {
+		return fmt.Errorf("invalid contact ID: %s", contact.ID)
 	}
 
-	filePath := getContactFilePath(contact.Name)
+	contact = canonicalize(contact)
+	if err := validateIdentifiers(contact, opts); err != nil {
+		return err
+	}
 
-	data, err := yaml.Marshal(&contact)
+	store, err := currentStore()
 	if err != nil {
-		return fmt.Errorf("failed to marshal contact: %w", err)
+		return err
 	}
-
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write contact file: %w", err)
+	if err := store.Save(contact); err != nil {
+		return err
 	}
 
 	InvalidateCache()
@@ -78,43 +128,43 @@ func SaveContact(contact Contact) error {
 	return nil
 }
 
-// LoadContact loads a contact from a YAML file by name.
-func LoadContact(name string) (*Contact, error) {
-	filePath := getContactFilePath(name)
-
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("contact not found: %s", name)
-		}
-		return nil, fmt.Errorf("failed to read contact file: %w", err)
+// LoadContact loads a contact from the active store by ID.
+func LoadContact(id string) (*Contact, error) {
+	if !isValidContactID(id) {
+		return nil, fmt.Errorf("invalid contact ID: %s", id)
 	}
 
-	var contact Contact
-	if err := yaml.Unmarshal(data, &contact); err != nil {
-		return nil, fmt.Errorf("failed to parse contact file: %w", err)
+	store, err := currentStore()
+	if err != nil {
+		return nil, err
 	}
-
-	return &contact, nil
+	return store.Load(id)
 }
 
-// DeleteContact deletes a contact's YAML file.
-func DeleteContact(name string) error {
-	filePath := getContactFilePath(name)
+// DeleteContact deletes a contact from the active store by ID and removes it from any groups.
+func DeleteContact(id string) error {
+	if !isValidContactID(id) {
+		return fmt.Errorf("invalid contact ID: %s", id)
+	}
 
-	if err := os.Remove(filePath); err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("contact not found: %s", name)
-		}
-		return fmt.Errorf("failed to delete contact: %w", err)
+	store, err := currentStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(id); err != nil {
+		return err
 	}
 
 	InvalidateCache()
 
+	if err := replaceGroupMember([]string{id}, ""); err != nil {
+		return fmt.Errorf("contact deleted but failed to remove it from groups: %w", err)
+	}
+
 	return nil
 }
 
-// ListContacts returns all contacts from the contacts directory.
+// ListContacts returns all contacts from the active store, favourites first and then by name.
 // Results are cached for 30 seconds to improve performance.
 func ListContacts() ([]Contact, error) {
 	contactCacheMutex.RLock()
@@ -131,54 +181,203 @@ func ListContacts() ([]Contact, error) {
 		return contactCache, nil
 	}
 
-	dir := GetContactsDir()
-
-	if err := ensureContactsDir(); err != nil {
-		return nil, fmt.Errorf("failed to create contacts directory: %w", err)
+	store, err := currentStore()
+	if err != nil {
+		return nil, err
 	}
-
-	entries, err := os.ReadDir(dir)
+	contacts, err := store.List()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read contacts directory: %w", err)
+		return nil, err
 	}
+	if contacts == nil {
+		contacts = []Contact{}
+	}
+
+	sort.SliceStable(contacts, func(i, j int) bool {
+		if contacts[i].Favorite != contacts[j].Favorite {
+			return contacts[i].Favorite
+		}
+		return strings.ToLower(contacts[i].Name) < strings.ToLower(contacts[j].Name)
+	})
 
-	var contacts []Contact
+	// When duplicates share a number or email, the first contact in sorted order wins,
+	// so lookups don't depend on the order contacts are read in.
 	lookupMap := make(map[string]string)
+	for _, contact := range contacts {
+		for _, identifier := range append(append([]string{}, contact.PhoneNumbers...), contact.Emails...) {
+			normalized := contact.Canonical(identifier)
+			if _, exists := lookupMap[normalized]; !exists {
+				lookupMap[normalized] = contact.DisplayName()
+			}
+		}
+	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yml") {
-			continue
+	if !maps.Equal(lookupMap, contactLookupMap) {
+		cacheGeneration.Add(1)
+	}
+	contactCache = contacts
+	contactLookupMap = lookupMap
+	contactCacheTime = time.Now()
+
+	return contacts, nil
+}
+
+// AddHandle records a phone number or email for the contact called name, creating the contact
+// if no contact (or nickname) has that name. Returns the saved contact.
+func AddHandle(name, handle string) (Contact, error) {
+	name = strings.TrimSpace(name)
+	handle = strings.TrimSpace(handle)
+	if handle == "" {
+		return Contact{}, fmt.Errorf("handle cannot be empty")
+	}
+
+	contact := Contact{Name: name}
+	if existing := FindContactByName(name); existing != nil {
+		contact = *existing
+	}
+
+	for _, identifier := range append(append([]string{}, contact.PhoneNumbers...), contact.Emails...) {
+		if NormalizeIdentifier(identifier) == NormalizeIdentifier(handle) {
+			return contact, nil
 		}
+	}
 
-		filePath := filepath.Join(dir, entry.Name())
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			continue
+	if strings.Contains(handle, "@") {
+		contact.Emails = append(contact.Emails, handle)
+	} else {
+		contact.PhoneNumbers = append(contact.PhoneNumbers, handle)
+	}
+
+	if contact.ID == "" {
+		contact.ID = NewContactID()
+	}
+	if err := SaveContact(contact); err != nil {
+		return Contact{}, err
+	}
+	return contact, nil
+}
+
+// ImportContacts saves each of the given contacts unless its name or any of its phone
+// numbers or emails already belongs to an existing contact. Malformed phone numbers and
+// emails are left out of the saved contacts.
+// Returns the number of contacts imported and skipped.
+func ImportContacts(incoming []Contact) (int, int, error) {
+	existing, err := ListContacts()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	knownNames := make(map[string]bool)
+	knownIdentifiers := make(map[string]bool)
+	remember := func(contact Contact) {
+		knownNames[strings.ToLower(strings.TrimSpace(contact.Name))] = true
+		for _, phone := range contact.PhoneNumbers {
+			knownIdentifiers[contact.Canonical(phone)] = true
 		}
+		for _, email := range contact.Emails {
+			knownIdentifiers[contact.Canonical(email)] = true
+		}
+	}
+	for _, contact := range existing {
+		remember(contact)
+	}
 
-		var contact Contact
-		if err := yaml.Unmarshal(data, &contact); err != nil {
+	imported, skipped := 0, 0
+	for _, contact := range incoming {
+		contact, _ = dropInvalidIdentifiers(contact)
+		duplicate := knownNames[strings.ToLower(strings.TrimSpace(contact.Name))]
+		for _, identifier := range append(append([]string{}, contact.PhoneNumbers...), contact.Emails...) {
+			if knownIdentifiers[NormalizeIdentifier(identifier)] {
+				duplicate = true
+			}
+		}
+		if duplicate {
+			skipped++
 			continue
 		}
 
-		contacts = append(contacts, contact)
+		if err := saveContact(contact, saveOptions{ownersChecked: true}); err != nil {
+			return imported, skipped, fmt.Errorf("failed to import %s: %w", contact.Name, err)
+		}
+		remember(contact)
+		imported++
+	}
 
-		for _, phone := range contact.PhoneNumbers {
-			normalized := normalizeIdentifier(phone)
-			lookupMap[normalized] = contact.Name
+	return imported, skipped, nil
+}
+
+// ListTags returns every tag used by at least one contact, sorted case-insensitively.
+func ListTags() ([]string, error) {
+	contacts, err := ListContacts()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var tags []string
+	for _, contact := range contacts {
+		for _, tag := range contact.Tags {
+			key := strings.ToLower(tag)
+			if tag == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			tags = append(tags, tag)
 		}
+	}
 
-		for _, email := range contact.Emails {
-			normalized := strings.ToLower(strings.TrimSpace(email))
-			lookupMap[normalized] = contact.Name
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+	return tags, nil
+}
+
+// ParseTags splits a comma-separated list of tags, trimming spaces and a leading "#"
+// and dropping empty and duplicate entries.
+func ParseTags(s string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
 		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
 	}
+	return tags
+}
 
-	contactCache = contacts
-	contactLookupMap = lookupMap
-	contactCacheTime = time.Now()
+// ValidateBirthday checks that birthday is empty, YYYY-MM-DD, or --MM-DD when the year is unknown.
+func ValidateBirthday(birthday string) error {
+	if birthday == "" {
+		return nil
+	}
 
-	return contacts, nil
+	layout := "2006-01-02"
+	value := birthday
+	if strings.HasPrefix(birthday, "--") {
+		value = "2000" + strings.TrimPrefix(birthday, "-")
+	}
+
+	if _, err := time.Parse(layout, value); err != nil {
+		return fmt.Errorf("birthday must be YYYY-MM-DD or --MM-DD")
+	}
+	return nil
+}
+
+// ValidateEmail checks that email looks like a single address of the form local@domain.
+func ValidateEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("invalid email address %q", email)
+	}
+
+	_, domain, _ := strings.Cut(email, "@")
+	if !strings.Contains(domain, ".") {
+		return fmt.Errorf("invalid email address %q: domain has no dot", email)
+	}
+	return nil
 }
 
 // InvalidateCache forces the contact cache to be refreshed on next access.
@@ -186,16 +385,43 @@ func InvalidateCache() {
 	contactCacheMutex.Lock()
 	defer contactCacheMutex.Unlock()
 	contactCacheTime = time.Time{}
+	cacheGeneration.Add(1)
+}
+
+// Generation returns a counter that changes whenever the names local or AddressBook contacts
+// give to handles change, or the contact cache is invalidated. Callers that cache names derived from contacts compare it to
+// know when to drop their cache.
+func Generation() uint64 {
+	return cacheGeneration.Load()
 }
 
 // FindContactByIdentifier searches for a contact by phone number or email.
-// Returns the contact name if found, empty string otherwise.
-// Uses an optimized lookup map for O(1) performance.
+// Returns the contact's display name (nickname if set) if found, empty string otherwise.
+// Uses an optimized lookup map for O(1) performance, or the store's identifier index when
+// the cache has expired and the backend has one.
 func FindContactByIdentifier(identifier string) string {
 	if identifier == "" {
 		return ""
 	}
 
+	contactCacheMutex.RLock()
+	fresh := time.Since(contactCacheTime) < cacheDuration && contactCache != nil
+	contactCacheMutex.RUnlock()
+
+	if !fresh {
+		if store, err := currentStore(); err == nil {
+			if index, ok := store.(identifierIndex); ok {
+				contact, err := index.FindByIdentifier(NormalizeIdentifier(identifier))
+				if err == nil {
+					if contact == nil {
+						return ""
+					}
+					return contact.DisplayName()
+				}
+			}
+		}
+	}
+
 	_, err := ListContacts()
 	if err != nil {
 		return ""
@@ -205,7 +431,7 @@ func FindContactByIdentifier(identifier string) string {
 	defer contactCacheMutex.RUnlock()
 
 	identifier = strings.TrimSpace(identifier)
-	normalizedIdentifier := normalizeIdentifier(identifier)
+	normalizedIdentifier := NormalizeIdentifier(identifier)
 
 	if name, ok := contactLookupMap[normalizedIdentifier]; ok {
 		return name
@@ -214,25 +440,36 @@ func FindContactByIdentifier(identifier string) string {
 	return ""
 }
 
-// normalizeIdentifier removes non-numeric characters from phone numbers for comparison.
-func normalizeIdentifier(s string) string {
-	if strings.Contains(s, "@") {
-		return strings.ToLower(s)
+// FindContactByName returns the contact whose name or nickname matches name case-insensitively.
+// Returns nil if no contact has that name.
+func FindContactByName(name string) *Contact {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
 	}
 
-	result := ""
-	for _, r := range s {
-		if (r >= '0' && r <= '9') || r == '+' {
-			result += string(r)
-		}
+	contacts, err := ListContacts()
+	if err != nil {
+		return nil
 	}
 
-	if strings.HasPrefix(result, "1") && len(result) == 11 {
-		result = result[1:]
+	for _, contact := range contacts {
+		if strings.EqualFold(contact.Name, name) || strings.EqualFold(contact.Nickname, name) {
+			found := contact
+			return &found
+		}
 	}
-	if strings.HasPrefix(result, "+1") && len(result) == 12 {
-		result = result[2:]
+
+	return nil
+}
+
+// NormalizeIdentifier lowercases emails and converts phone numbers to E.164 using the
+// default region, so contact numbers and chat.db handles compare equal.
+func NormalizeIdentifier(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "@") {
+		return strings.ToLower(s)
 	}
 
-	return result
+	return NormalizePhoneNumber(s, DefaultRegion())
 }
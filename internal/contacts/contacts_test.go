@@ -0,0 +1,159 @@
+package contacts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/saravenpi/chime/internal/config"
+)
+
+// expireContactCache makes the next ListContacts reload from the store without going through
+// InvalidateCache, as the 30 second expiry does.
+func expireContactCache() {
+	contactCacheMutex.Lock()
+	contactCacheTime = time.Time{}
+	contactCacheMutex.Unlock()
+}
+
+func TestGenerationOnlyChangesWithContacts(t *testing.T) {
+	previous := config.Current()
+	t.Cleanup(func() {
+		config.Set(previous)
+		InvalidateCache()
+	})
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	cfg.ContactsBackend = "yaml"
+	config.Set(cfg)
+
+	if err := SaveContact(Contact{Name: "Alice Martin", PhoneNumbers: []string{"+447700900123"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ListContacts(); err != nil {
+		t.Fatal(err)
+	}
+	start := Generation()
+
+	expireContactCache()
+	if _, err := ListContacts(); err != nil {
+		t.Fatal(err)
+	}
+	if got := Generation(); got != start {
+		t.Errorf("Generation() changed from %d to %d on a reload with no changes", start, got)
+	}
+
+	if err := (yamlStore{}).Save(Contact{ID: NewContactID(), Name: "Bob Smith", Emails: []string{"bob@example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+	expireContactCache()
+	if _, err := ListContacts(); err != nil {
+		t.Fatal(err)
+	}
+	if got := Generation(); got == start {
+		t.Error("Generation() unchanged after a reload found a new contact")
+	}
+
+	before := Generation()
+	InvalidateCache()
+	if got := Generation(); got == before {
+		t.Error("Generation() unchanged after InvalidateCache")
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	setRegionForTest(t, "GB")
+
+	all := []Contact{
+		{Name: "Alice Martin", PhoneNumbers: []string{"+44 7700 900123"}},
+		{Name: "Doe, John"},
+		{Name: "Ali M", PhoneNumbers: []string{"07700 900123"}},
+		{Name: "Jonathan Smith"},
+		{Name: "john doe."},
+		{Name: "Jonathon Smith"},
+		{Name: "Anne"},
+		{Name: "Anna"},
+		{Name: "Xavier", Emails: []string{"x@example.com"}},
+		{Name: "Yolanda", Emails: []string{"X@Example.com"}, PhoneNumbers: []string{"+15555550100"}},
+		{Name: "Zoe", PhoneNumbers: []string{"+1 555 555 0100"}},
+		{Name: "Zed"},
+	}
+
+	type group struct {
+		names   []string
+		reasons []string
+	}
+	want := []group{
+		{[]string{"Alice Martin", "Ali M"}, []string{"share +447700900123"}},
+		{[]string{"Doe, John", "john doe."}, []string{"same name"}},
+		{[]string{"Jonathan Smith", "Jonathon Smith"}, []string{"similar names"}},
+		{[]string{"Xavier", "Yolanda", "Zoe"}, []string{"share x@example.com", "share +15555550100"}},
+	}
+
+	var got []group
+	for _, g := range FindDuplicates(all) {
+		var names []string
+		for _, contact := range g.Contacts {
+			names = append(names, contact.Name)
+		}
+		got = append(got, group{names, g.Reasons})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicates() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestMergeContacts(t *testing.T) {
+	setRegionForTest(t, "GB")
+
+	primary := Contact{
+		ID:           "primary",
+		Name:         "Alice Martin",
+		PhoneNumbers: []string{"+44 7700 900123"},
+		Labels:       map[string]string{"+44 7700 900123": "mobile"},
+		Normalized:   map[string]string{"+44 7700 900123": "+447700900123"},
+		Tags:         []string{"Family"},
+		Notes:        "Met at school",
+	}
+	other := Contact{
+		ID:           "other",
+		Name:         "Ali M",
+		Nickname:     "Ali",
+		Birthday:     "1990-01-15",
+		PhoneNumbers: []string{"07700 900123", "020 7946 0018"},
+		Emails:       []string{"alice@example.com"},
+		Labels:       map[string]string{"07700 900123": "work", "alice@example.com": "home"},
+		Tags:         []string{"family", "Climbing"},
+		Favorite:     true,
+		Notes:        "Vegetarian",
+	}
+	third := Contact{
+		ID:           "third",
+		Name:         "A. Martin",
+		Nickname:     "Al",
+		Organization: "Acme",
+		Notes:        "Met at school",
+	}
+
+	got := MergeContacts(primary, other, third)
+	want := Contact{
+		ID:           "primary",
+		Name:         "Alice Martin",
+		Nickname:     "Ali",
+		Organization: "Acme",
+		Birthday:     "1990-01-15",
+		PhoneNumbers: []string{"+44 7700 900123", "020 7946 0018"},
+		Emails:       []string{"alice@example.com"},
+		Labels:       map[string]string{"+44 7700 900123": "mobile", "alice@example.com": "home"},
+		Normalized:   map[string]string{"+44 7700 900123": "+447700900123"},
+		Tags:         []string{"Family", "Climbing"},
+		Favorite:     true,
+		Notes:        "Met at school\n\nVegetarian",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeContacts() =\n%#v\nwant\n%#v", got, want)
+	}
+	if primary.PhoneNumbers[0] != "+44 7700 900123" || len(primary.PhoneNumbers) != 1 {
+		t.Errorf("MergeContacts() modified the primary contact: %#v", primary)
+	}
+}
@@ -0,0 +1,453 @@
+package contacts
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVMapping maps CSV column headers to contact fields. Headers are compared
+// case-insensitively and may contain * wildcards ("Phone * - Value"). Fields are name,
+// first_name, middle_name, last_name, nickname, organization, birthday, notes, tags,
+// favorite, phone, email and ignore; phone and email may carry a label ("phone:mobile").
+// Without one, a phone or email in a "X - Value" column takes its label from the
+// "X - Label" or "X - Type" column next to it.
+type CSVMapping map[string]string
+
+// CSVPresets are the built-in mappings for common exports.
+var CSVPresets = map[string]CSVMapping{
+	"google": {
+		"Name":                  "name",
+		"First Name":            "first_name",
+		"Given Name":            "first_name",
+		"Middle Name":           "middle_name",
+		"Additional Name":       "middle_name",
+		"Last Name":             "last_name",
+		"Family Name":           "last_name",
+		"Nickname":              "nickname",
+		"Organization Name":     "organization",
+		"Organization 1 - Name": "organization",
+		"Birthday":              "birthday",
+		"Notes":                 "notes",
+		"Labels":                "tags",
+		"Group Membership":      "tags",
+		"Phone * - Value":       "phone",
+		"E-mail * - Value":      "email",
+	},
+	"outlook": {
+		"First Name":       "first_name",
+		"Middle Name":      "middle_name",
+		"Last Name":        "last_name",
+		"Nickname":         "nickname",
+		"Company":          "organization",
+		"Birthday":         "birthday",
+		"Notes":            "notes",
+		"Categories":       "tags",
+		"Mobile Phone":     "phone:mobile",
+		"Primary Phone":    "phone",
+		"Home Phone":       "phone:home",
+		"Home Phone 2":     "phone:home",
+		"Business Phone":   "phone:work",
+		"Business Phone 2": "phone:work",
+		"Other Phone":      "phone:other",
+		"E-mail Address":   "email",
+		"E-mail 2 Address": "email",
+		"E-mail 3 Address": "email",
+	},
+}
+
+var csvFields = map[string]bool{
+	"name": true, "first_name": true, "middle_name": true, "last_name": true,
+	"nickname": true, "organization": true, "birthday": true, "notes": true,
+	"tags": true, "favorite": true, "phone": true, "email": true, "ignore": true,
+}
+
+// csvListSeparator separates several values in one Google export cell.
+const csvListSeparator = ":::"
+
+// ParseCSVMapping parses custom column mappings written as "Header=field".
+func ParseCSVMapping(specs []string) (CSVMapping, error) {
+	mapping := make(CSVMapping)
+	for _, spec := range specs {
+		header, field, ok := strings.Cut(spec, "=")
+		header, field = strings.TrimSpace(header), strings.ToLower(strings.TrimSpace(field))
+		if !ok || header == "" {
+			return nil, fmt.Errorf("invalid column mapping %q: expected Header=field", spec)
+		}
+		name, _, _ := strings.Cut(field, ":")
+		if !csvFields[name] {
+			return nil, fmt.Errorf("invalid column mapping %q: unknown field %q", spec, name)
+		}
+		mapping[header] = field
+	}
+	return mapping, nil
+}
+
+// DetectCSVPreset returns the name of the preset matching a CSV header row, or "" if the
+// format isn't recognised.
+func DetectCSVPreset(header []string) string {
+	for _, column := range header {
+		lower := strings.ToLower(strings.TrimSpace(column))
+		switch {
+		case strings.HasSuffix(lower, " - value"), lower == "group membership":
+			return "google"
+		case lower == "e-mail address", lower == "mobile phone", lower == "business phone":
+			return "outlook"
+		}
+	}
+	return ""
+}
+
+type csvColumn struct {
+	field       string
+	label       string
+	labelColumn int
+}
+
+// resolveCSVColumns picks the field for each header. Exact header matches win over
+// wildcard patterns.
+func resolveCSVColumns(header []string, mapping CSVMapping) []csvColumn {
+	var patterns []string
+	exact := make(map[string]string)
+	for key, field := range mapping {
+		lower := strings.ToLower(key)
+		if strings.Contains(lower, "*") {
+			patterns = append(patterns, key)
+		} else {
+			exact[lower] = field
+		}
+	}
+	sort.Strings(patterns)
+
+	columns := make([]csvColumn, len(header))
+	for i, h := range header {
+		lower := strings.ToLower(strings.TrimSpace(h))
+		spec, ok := exact[lower]
+		if !ok {
+			for _, pattern := range patterns {
+				if matched, _ := path.Match(strings.ToLower(pattern), lower); matched {
+					spec = mapping[pattern]
+					break
+				}
+			}
+		}
+
+		field, label, _ := strings.Cut(spec, ":")
+		columns[i] = csvColumn{field: field, label: label, labelColumn: -1}
+
+		if (field == "phone" || field == "email") && label == "" && strings.HasSuffix(lower, " - value") {
+			base := strings.TrimSuffix(lower, " - value")
+			for j, other := range header {
+				o := strings.ToLower(strings.TrimSpace(other))
+				if o == base+" - label" || o == base+" - type" {
+					columns[i].labelColumn = j
+				}
+			}
+		}
+	}
+	return columns
+}
+
+// ParseCSV reads contacts from a CSV file with a header row using mapping. Rows without a
+// name (or first/last name) are skipped; each skipped row or dropped value is described in
+// the returned warnings.
+func ParseCSV(r io.Reader, mapping CSVMapping) ([]Contact, []string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := readCSVHeader(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := resolveCSVColumns(header, mapping)
+
+	var contacts []Contact
+	var warnings []string
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+
+		contact, rowWarnings := parseCSVRecord(record, columns)
+		for _, warning := range rowWarnings {
+			warnings = append(warnings, fmt.Sprintf("row %d: %s", row, warning))
+		}
+		if contact.Name == "" {
+			warnings = append(warnings, fmt.Sprintf("row %d: skipped, no name", row))
+			continue
+		}
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, warnings, nil
+}
+
+// readCSVHeader reads the header row, dropping the byte order mark some exports start with.
+func readCSVHeader(reader *csv.Reader) ([]string, error) {
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	return header, nil
+}
+
+func parseCSVRecord(record []string, columns []csvColumn) (Contact, []string) {
+	var contact Contact
+	var warnings []string
+	var nameParts [3]string
+	seen := make(map[string]bool)
+
+	for i, column := range columns {
+		if i >= len(record) {
+			break
+		}
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+
+		switch column.field {
+		case "name":
+			contact.Name = value
+		case "first_name":
+			nameParts[0] = value
+		case "middle_name":
+			nameParts[1] = value
+		case "last_name":
+			nameParts[2] = value
+		case "nickname":
+			contact.Nickname = value
+		case "organization":
+			contact.Organization = value
+		case "notes":
+			contact.Notes = value
+		case "birthday":
+			if birthday := normalizeCSVDate(value); birthday != "" {
+				contact.Birthday = birthday
+			} else if strings.Trim(value, "0/") != "" {
+				warnings = append(warnings, fmt.Sprintf("ignored birthday %q", value))
+			}
+		case "favorite":
+			contact.Favorite, _ = strconv.ParseBool(value)
+		case "tags":
+			for _, tag := range splitCSVList(value) {
+				switch {
+				case strings.EqualFold(tag, "* starred"):
+					contact.Favorite = true
+				case strings.HasPrefix(tag, "* "):
+					// Google system groups such as "* myContacts".
+				case !contact.HasTag(tag):
+					contact.Tags = append(contact.Tags, tag)
+				}
+			}
+		case "phone", "email":
+			label := column.label
+			if label == "" && column.labelColumn >= 0 && column.labelColumn < len(record) {
+				label = csvLabel(record[column.labelColumn])
+			}
+			for _, identifier := range strings.Split(value, csvListSeparator) {
+				identifier = strings.TrimSpace(identifier)
+				key := NormalizeIdentifier(identifier)
+				if identifier == "" || seen[key] {
+					continue
+				}
+				seen[key] = true
+				if column.field == "phone" {
+					contact.PhoneNumbers = append(contact.PhoneNumbers, identifier)
+				} else {
+					contact.Emails = append(contact.Emails, identifier)
+				}
+				setLabel(&contact, identifier, label)
+			}
+		}
+	}
+
+	if contact.Name == "" {
+		contact.Name = strings.Join(strings.Fields(strings.Join(nameParts[:], " ")), " ")
+	}
+	return contact, warnings
+}
+
+// splitCSVList splits a cell holding several values: Google separates them with ":::",
+// Outlook categories with ";".
+func splitCSVList(value string) []string {
+	sep := ";"
+	if strings.Contains(value, csvListSeparator) {
+		sep = csvListSeparator
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// csvLabel turns an exported type such as "* Mobile" or "Work" into a contact label.
+func csvLabel(value string) string {
+	label := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "*")))
+	if label == "cell" || label == "iphone" {
+		return "mobile"
+	}
+	return label
+}
+
+var csvDateLayouts = []string{"1/2/2006", "2006/01/02", "Jan 2, 2006", "January 2, 2006", "2 January 2006"}
+
+// normalizeCSVDate converts a birthday to YYYY-MM-DD or --MM-DD. Returns "" if the date
+// can't be read, including Outlook's "0/0/00" placeholder.
+func normalizeCSVDate(value string) string {
+	if ValidateBirthday(value) == nil {
+		return value
+	}
+	for _, layout := range csvDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+// WriteCSV writes contacts to w in the Google Contacts CSV format, which Google and the
+// "google" preset can import. Labels become the phone and email types, tags the group
+// memberships and favourites are starred.
+func WriteCSV(w io.Writer, contacts []Contact) error {
+	maxPhones, maxEmails := 1, 1
+	for _, contact := range contacts {
+		maxPhones = max(maxPhones, len(contact.PhoneNumbers))
+		maxEmails = max(maxEmails, len(contact.Emails))
+	}
+
+	header := []string{"Name", "Nickname", "Birthday", "Notes", "Group Membership", "Organization 1 - Name"}
+	for i := 1; i <= maxPhones; i++ {
+		header = append(header, fmt.Sprintf("Phone %d - Type", i), fmt.Sprintf("Phone %d - Value", i))
+	}
+	for i := 1; i <= maxEmails; i++ {
+		header = append(header, fmt.Sprintf("E-mail %d - Type", i), fmt.Sprintf("E-mail %d - Value", i))
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, contact := range contacts {
+		groups := []string{"* myContacts"}
+		if contact.Favorite {
+			groups = append(groups, "* starred")
+		}
+		groups = append(groups, contact.Tags...)
+
+		record := []string{
+			contact.Name,
+			contact.Nickname,
+			contact.Birthday,
+			contact.Notes,
+			strings.Join(groups, " "+csvListSeparator+" "),
+			contact.Organization,
+		}
+		for i := 0; i < maxPhones; i++ {
+			record = append(record, csvIdentifierColumns(contact, contact.PhoneNumbers, i)...)
+		}
+		for i := 0; i < maxEmails; i++ {
+			record = append(record, csvIdentifierColumns(contact, contact.Emails, i)...)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// csvIdentifierColumns returns the type and value cells for identifiers[i], or two empty
+// cells if the contact has fewer identifiers.
+func csvIdentifierColumns(contact Contact, identifiers []string, i int) []string {
+	if i >= len(identifiers) {
+		return []string{"", ""}
+	}
+	label := contact.Label(identifiers[i])
+	if label != "" {
+		label = strings.ToUpper(label[:1]) + label[1:]
+	}
+	return []string{label, identifiers[i]}
+}
+
+// ReadCSVFile parses the CSV file at path. preset names one of CSVPresets; when empty the
+// preset is detected from the header. custom mappings are applied on top of the preset.
+// Returns the contacts, warnings about skipped rows and the preset used.
+func ReadCSVFile(filePath, preset string, custom CSVMapping) ([]Contact, []string, string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to read CSV file: %w", err)
+	}
+
+	if preset == "" {
+		header, err := readCSVHeader(csv.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return nil, nil, "", err
+		}
+		preset = DetectCSVPreset(header)
+		if preset == "" && len(custom) == 0 {
+			return nil, nil, "", fmt.Errorf("unrecognised CSV format: choose a preset (google, outlook) or map the columns")
+		}
+	}
+
+	mapping := make(CSVMapping)
+	if preset != "" {
+		presetMapping, ok := CSVPresets[preset]
+		if !ok {
+			return nil, nil, "", fmt.Errorf("unknown CSV preset %q (available: google, outlook)", preset)
+		}
+		for header, field := range presetMapping {
+			mapping[header] = field
+		}
+	}
+	for header, field := range custom {
+		mapping[header] = field
+	}
+
+	parsed, warnings, err := ParseCSV(bytes.NewReader(data), mapping)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return parsed, warnings, preset, nil
+}
+
+// ExportCSVFile writes contacts to a CSV file at path, replacing it if it exists.
+func ExportCSVFile(filePath string, contacts []Contact) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+
+	if err := WriteCSV(f, contacts); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,221 @@
+package contacts
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const googleCSV = "\ufeffName,Given Name,Family Name,Nickname,Birthday,Group Membership,Phone 1 - Type,Phone 1 - Value,Phone 2 - Type,Phone 2 - Value,E-mail 1 - Type,E-mail 1 - Value\n" +
+	"Alice Martin,Alice,Martin,Ali,1990-01-15,* myContacts ::: * starred ::: Family,* Mobile,+44 7700 900123 ::: 07700 900123,Work,020 7946 0018,* Home,alice@example.com\n" +
+	",Bob,Smith,,--04-12,* myContacts,,,,,,bob@example.com\n" +
+	",,,,13/45/2000,,,,,,,\n"
+
+const outlookCSV = "First Name,Middle Name,Last Name,Company,Birthday,Categories,Mobile Phone,Business Phone,E-mail Address\n" +
+	"Jean,Pierre,Dupont,Acme,4/12/1985,Work;Golf,06 12 34 56 78,,jean@example.fr\n" +
+	"Carol,,Jones,,0/0/00,,,+1 415 555 0100,\n"
+
+func TestParseCSV(t *testing.T) {
+	setRegionForTest(t, "GB")
+
+	tests := []struct {
+		name     string
+		input    string
+		mapping  CSVMapping
+		want     []Contact
+		warnings []string
+	}{
+		{
+			name:    "google",
+			input:   googleCSV,
+			mapping: CSVPresets["google"],
+			want: []Contact{
+				{
+					Name:         "Alice Martin",
+					Nickname:     "Ali",
+					Birthday:     "1990-01-15",
+					PhoneNumbers: []string{"+44 7700 900123", "020 7946 0018"},
+					Emails:       []string{"alice@example.com"},
+					Labels: map[string]string{
+						"+44 7700 900123":   "mobile",
+						"020 7946 0018":     "work",
+						"alice@example.com": "home",
+					},
+					Tags:     []string{"Family"},
+					Favorite: true,
+				},
+				{
+					Name:     "Bob Smith",
+					Birthday: "--04-12",
+					Emails:   []string{"bob@example.com"},
+				},
+			},
+			warnings: []string{`row 4: ignored birthday "13/45/2000"`, "row 4: skipped, no name"},
+		},
+		{
+			name:    "outlook",
+			input:   outlookCSV,
+			mapping: CSVPresets["outlook"],
+			want: []Contact{
+				{
+					Name:         "Jean Pierre Dupont",
+					Organization: "Acme",
+					Birthday:     "1985-04-12",
+					PhoneNumbers: []string{"06 12 34 56 78"},
+					Emails:       []string{"jean@example.fr"},
+					Labels:       map[string]string{"06 12 34 56 78": "mobile"},
+					Tags:         []string{"Work", "Golf"},
+				},
+				{
+					Name:         "Carol Jones",
+					PhoneNumbers: []string{"+1 415 555 0100"},
+					Labels:       map[string]string{"+1 415 555 0100": "work"},
+				},
+			},
+		},
+		{
+			name: "exact headers win over wildcards",
+			input: "full name,Phone 1 - Type,Phone 1 - Value,Phone 2 - Type,Phone 2 - Value,Fav\n" +
+				"Dana Kim,Home,020 7946 0018,Mobile,07700 900456,true\n",
+			mapping: CSVMapping{
+				"Full Name":       "name",
+				"Phone * - Value": "phone",
+				"Phone 1 - Value": "ignore",
+				"Phone 2 - Value": "phone:work",
+				"Fav":             "favorite",
+			},
+			want: []Contact{{
+				Name:         "Dana Kim",
+				PhoneNumbers: []string{"07700 900456"},
+				Labels:       map[string]string{"07700 900456": "work"},
+				Favorite:     true,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := ParseCSV(strings.NewReader(tt.input), tt.mapping)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCSV() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("ParseCSV() warnings = %q, want %q", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestParseCSVMapping(t *testing.T) {
+	got, err := ParseCSVMapping([]string{"Full Name=name", " Cell = Phone:Mobile "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CSVMapping{"Full Name": "name", "Cell": "phone:mobile"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCSVMapping() = %v, want %v", got, want)
+	}
+
+	for _, spec := range []string{"Full Name", "=name", "Age=age"} {
+		if _, err := ParseCSVMapping([]string{spec}); err == nil {
+			t.Errorf("ParseCSVMapping(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestDetectCSVPreset(t *testing.T) {
+	tests := []struct {
+		header []string
+		want   string
+	}{
+		{[]string{"Name", "Given Name", "Phone 1 - Type", "Phone 1 - Value"}, "google"},
+		{[]string{"Name", "Group Membership"}, "google"},
+		{[]string{"First Name", "Last Name", "E-mail Address"}, "outlook"},
+		{[]string{"First Name", " mobile phone "}, "outlook"},
+		{[]string{"First Name", "Business Phone"}, "outlook"},
+		{[]string{"Full Name", "Cell", "Mail"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := DetectCSVPreset(tt.header); got != tt.want {
+			t.Errorf("DetectCSVPreset(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	contacts := []Contact{
+		{
+			Name:         "Alice Martin",
+			Nickname:     "Ali",
+			Organization: "Acme, Inc",
+			Birthday:     "1990-01-15",
+			PhoneNumbers: []string{"+447700900123", "+442079460018"},
+			Emails:       []string{"alice@example.com"},
+			Labels: map[string]string{
+				"+447700900123":     "mobile",
+				"+442079460018":     "holiday-home",
+				"alice@example.com": "work",
+			},
+			Tags:     []string{"family", "Climbing club"},
+			Favorite: true,
+			Notes:    "line one\nline \"two\"",
+		},
+		{
+			Name:     "Bob Smith",
+			Birthday: "--04-12",
+			Emails:   []string{"bob@example.com", "robert@example.org"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, contacts); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "contacts.csv")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, warnings, preset, err := ReadCSVFile(path, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preset != "google" {
+		t.Errorf("ReadCSVFile() preset = %q, want google", preset)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("ReadCSVFile() warnings = %q, want none", warnings)
+	}
+	if !reflect.DeepEqual(got, contacts) {
+		t.Errorf("round trip =\n%#v\nwant\n%#v", got, contacts)
+	}
+}
+
+func TestReadCSVFileRejectsUnknownFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.csv")
+	if err := os.WriteFile(path, []byte("Full Name,Cell\nDana Kim,07700 900456\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := ReadCSVFile(path, "", nil); err == nil {
+		t.Error("ReadCSVFile() with an unrecognised header succeeded, want an error")
+	}
+	if _, _, _, err := ReadCSVFile(path, "yahoo", nil); err == nil {
+		t.Error("ReadCSVFile() with an unknown preset succeeded, want an error")
+	}
+
+	got, _, _, err := ReadCSVFile(path, "", CSVMapping{"Full Name": "name", "Cell": "phone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "Dana Kim" || !reflect.DeepEqual(got[0].PhoneNumbers, []string{"07700 900456"}) {
+		t.Errorf("ReadCSVFile() with a custom mapping = %#v", got)
+	}
+}
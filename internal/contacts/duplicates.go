@@ -0,0 +1,252 @@
+package contacts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateGroup is a set of contacts that probably describe the same person.
+type DuplicateGroup struct {
+	Contacts []Contact
+	// Reasons explains why the contacts were grouped, e.g. "share +447700900123".
+	Reasons []string
+}
+
+// FindDuplicates groups contacts that share a normalised phone number or email, or whose
+// names are the same once case, punctuation and word order are ignored, or differ by a single
+// typo (for names of at least minTypoNameLength letters). Contacts linked
+// through a chain of matches end up in the same group. Only groups of two or more are returned.
+func FindDuplicates(contacts []Contact) []DuplicateGroup {
+	parent := make([]int, len(contacts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	reasons := make(map[int][]string)
+	union := func(a, b int, reason string) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			parent[rb] = ra
+			reasons[ra] = append(reasons[ra], reasons[rb]...)
+			delete(reasons, rb)
+		}
+		for _, r := range reasons[ra] {
+			if r == reason {
+				return
+			}
+		}
+		reasons[ra] = append(reasons[ra], reason)
+	}
+
+	firstByIdentifier := make(map[string]int)
+	firstByName := make(map[string]int)
+	keys := make([]string, len(contacts))
+
+	for i, contact := range contacts {
+		for _, identifier := range append(append([]string{}, contact.PhoneNumbers...), contact.Emails...) {
+			key := contact.Canonical(identifier)
+			if key == "" {
+				continue
+			}
+			if j, ok := firstByIdentifier[key]; ok {
+				union(j, i, "share "+key)
+			} else {
+				firstByIdentifier[key] = i
+			}
+		}
+
+		if key := nameKey(contact.Name); key != "" {
+			keys[i] = key
+			if j, ok := firstByName[key]; ok {
+				union(j, i, "same name")
+			} else {
+				firstByName[key] = i
+			}
+		}
+	}
+
+	for i := range contacts {
+		for j := i + 1; j < len(contacts); j++ {
+			if keys[i] != keys[j] && len(keys[i]) >= minTypoNameLength && oneEditApart(keys[i], keys[j]) {
+				union(i, j, "similar names")
+			}
+		}
+	}
+
+	members := make(map[int][]Contact)
+	var roots []int
+	for i, contact := range contacts {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], contact)
+	}
+
+	var groups []DuplicateGroup
+	for _, root := range roots {
+		if len(members[root]) < 2 {
+			continue
+		}
+		groups = append(groups, DuplicateGroup{Contacts: members[root], Reasons: reasons[find(root)]})
+	}
+	return groups
+}
+
+// minTypoNameLength is the shortest name checked for single-typo matches; shorter names
+// ("Ann" and "Dan") are too likely to be different people.
+const minTypoNameLength = 6
+
+// oneEditApart reports whether a and b differ by exactly one inserted, deleted or changed rune.
+func oneEditApart(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) > len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(rb)-len(ra) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(ra) && ra[i] == rb[i] {
+		i++
+	}
+	if len(ra) == len(rb) {
+		return i < len(ra) && string(ra[i+1:]) == string(rb[i+1:])
+	}
+	return string(ra[i:]) == string(rb[i+1:])
+}
+
+// nameKey reduces a name to lowercase letters and digits with its words sorted,
+// so "Doe, John", "john doe" and "John  Doe." compare equal.
+func nameKey(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// MergeContacts combines others into primary. The primary keeps its ID and name; empty
+// fields are filled from the others in order, phone numbers, emails and tags are combined
+// without duplicates, notes are joined, and the contact is a favourite if any of them was.
+func MergeContacts(primary Contact, others ...Contact) Contact {
+	merged := primary
+	merged.PhoneNumbers = append([]string(nil), primary.PhoneNumbers...)
+	merged.Emails = append([]string(nil), primary.Emails...)
+	merged.Tags = append([]string(nil), primary.Tags...)
+	merged.Labels = make(map[string]string)
+	for identifier, label := range primary.Labels {
+		merged.Labels[identifier] = label
+	}
+	merged.Normalized = make(map[string]string)
+	for identifier, normalized := range primary.Normalized {
+		merged.Normalized[identifier] = normalized
+	}
+
+	seen := make(map[string]bool)
+	for _, identifier := range append(append([]string{}, merged.PhoneNumbers...), merged.Emails...) {
+		seen[primary.Canonical(identifier)] = true
+	}
+
+	var notes []string
+	if merged.Notes != "" {
+		notes = append(notes, merged.Notes)
+	}
+
+	for _, other := range others {
+		if merged.Nickname == "" {
+			merged.Nickname = other.Nickname
+		}
+		if merged.Organization == "" {
+			merged.Organization = other.Organization
+		}
+		if merged.Birthday == "" {
+			merged.Birthday = other.Birthday
+		}
+		merged.Favorite = merged.Favorite || other.Favorite
+
+		for _, phone := range other.PhoneNumbers {
+			if key := other.Canonical(phone); !seen[key] {
+				seen[key] = true
+				merged.PhoneNumbers = append(merged.PhoneNumbers, phone)
+				if label := other.Label(phone); label != "" {
+					merged.Labels[phone] = label
+				}
+				if normalized, ok := other.Normalized[phone]; ok {
+					merged.Normalized[phone] = normalized
+				}
+			}
+		}
+		for _, email := range other.Emails {
+			if key := other.Canonical(email); !seen[key] {
+				seen[key] = true
+				merged.Emails = append(merged.Emails, email)
+				if label := other.Label(email); label != "" {
+					merged.Labels[email] = label
+				}
+				if normalized, ok := other.Normalized[email]; ok {
+					merged.Normalized[email] = normalized
+				}
+			}
+		}
+		for _, tag := range other.Tags {
+			if !merged.HasTag(tag) {
+				merged.Tags = append(merged.Tags, tag)
+			}
+		}
+
+		if other.Notes != "" && !strings.Contains(strings.Join(notes, "\n"), other.Notes) {
+			notes = append(notes, other.Notes)
+		}
+	}
+
+	merged.Notes = strings.Join(notes, "\n\n")
+	if len(merged.Labels) == 0 {
+		merged.Labels = nil
+	}
+	if len(merged.Normalized) == 0 {
+		merged.Normalized = nil
+	}
+	return merged
+}
+
+// MergeAndSave merges others into primary with MergeContacts, saves the result, moves the
+// others' group memberships to it and deletes the others' files. Returns the merged contact.
+func MergeAndSave(primary Contact, others ...Contact) (Contact, error) {
+	merged := MergeContacts(primary, others...)
+
+	var otherIDs []string
+	for _, other := range others {
+		if other.ID != merged.ID {
+			otherIDs = append(otherIDs, other.ID)
+		}
+	}
+
+	if err := saveContact(merged, saveOptions{takeFrom: otherIDs}); err != nil {
+		return Contact{}, err
+	}
+	if err := replaceGroupMember(otherIDs, merged.ID); err != nil {
+		return merged, fmt.Errorf("merged into %s but failed to update groups: %w", merged.Name, err)
+	}
+
+	for _, other := range others {
+		if other.ID == merged.ID {
+			continue
+		}
+		if err := DeleteContact(other.ID); err != nil {
+			return merged, fmt.Errorf("merged into %s but failed to remove %s: %w", merged.Name, other.Name, err)
+		}
+	}
+
+	return merged, nil
+}
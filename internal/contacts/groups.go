@@ -0,0 +1,230 @@
+package contacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Group is a named list of contacts, e.g. "oncall" or "family".
+type Group struct {
+	Name string `yaml:"name"`
+	// Members holds contact IDs, so renaming a contact keeps it in its groups.
+	Members []string `yaml:"members,omitempty"`
+}
+
+// HasMember reports whether the contact with the given ID belongs to the group.
+func (g Group) HasMember(id string) bool {
+	for _, member := range g.Members {
+		if member == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGroupsPath returns the file contact groups are stored in (~/.chime/groups.yml).
+func GetGroupsPath() string {
+	return filepath.Join(filepath.Dir(GetContactsDir()), "groups.yml")
+}
+
+// ValidateGroupName checks that name can be typed as a recipient ("@name"): it must be
+// non-empty and contain no spaces, commas or "@".
+func ValidateGroupName(name string) error {
+	if name == "" {
+		return fmt.Errorf("group name cannot be empty")
+	}
+	if strings.ContainsAny(name, " \t,@") {
+		return fmt.Errorf("group name %q cannot contain spaces, commas or @", name)
+	}
+	return nil
+}
+
+// ListGroups returns all groups sorted by name.
+func ListGroups() ([]Group, error) {
+	data, err := os.ReadFile(GetGroupsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read groups: %w", err)
+	}
+
+	var groups []Group
+	if err := yaml.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse groups: %w", err)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	return groups, nil
+}
+
+// FindGroup returns the group with the given name, compared case-insensitively.
+// Returns nil if there is no such group.
+func FindGroup(name string) (*Group, error) {
+	groups, err := ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if strings.EqualFold(group.Name, name) {
+			found := group
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateGroup adds an empty group.
+func CreateGroup(name string) error {
+	name = strings.TrimSpace(name)
+	if err := ValidateGroupName(name); err != nil {
+		return err
+	}
+
+	return updateGroups(func(groups []Group) ([]Group, error) {
+		for _, group := range groups {
+			if strings.EqualFold(group.Name, name) {
+				return nil, fmt.Errorf("group %q already exists", group.Name)
+			}
+		}
+		return append(groups, Group{Name: name}), nil
+	})
+}
+
+// RenameGroup changes a group's name, keeping its members.
+func RenameGroup(oldName, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if err := ValidateGroupName(newName); err != nil {
+		return err
+	}
+
+	return updateGroups(func(groups []Group) ([]Group, error) {
+		index := -1
+		for i, group := range groups {
+			if strings.EqualFold(group.Name, oldName) {
+				index = i
+			} else if strings.EqualFold(group.Name, newName) {
+				return nil, fmt.Errorf("group %q already exists", group.Name)
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("group not found: %s", oldName)
+		}
+		groups[index].Name = newName
+		return groups, nil
+	})
+}
+
+// DeleteGroup removes a group. The contacts in it are not affected.
+func DeleteGroup(name string) error {
+	return updateGroups(func(groups []Group) ([]Group, error) {
+		for i, group := range groups {
+			if strings.EqualFold(group.Name, name) {
+				return append(groups[:i], groups[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("group not found: %s", name)
+	})
+}
+
+// SetGroupMember adds the contact with the given ID to a group, or removes it when member
+// is false.
+func SetGroupMember(name, contactID string, member bool) error {
+	return updateGroups(func(groups []Group) ([]Group, error) {
+		for i := range groups {
+			if !strings.EqualFold(groups[i].Name, name) {
+				continue
+			}
+			groups[i].Members = withoutMember(groups[i].Members, contactID)
+			if member {
+				groups[i].Members = append(groups[i].Members, contactID)
+			}
+			return groups, nil
+		}
+		return nil, fmt.Errorf("group not found: %s", name)
+	})
+}
+
+// GroupMembers returns the contacts in a group, in the contacts list order. Members whose
+// contact no longer exists are skipped.
+func GroupMembers(group Group) ([]Contact, error) {
+	all, err := ListContacts()
+	if err != nil {
+		return nil, err
+	}
+
+	var members []Contact
+	for _, contact := range all {
+		if group.HasMember(contact.ID) {
+			members = append(members, contact)
+		}
+	}
+	return members, nil
+}
+
+// replaceGroupMember moves every group membership of the contacts in oldIDs to newID, or
+// drops them when newID is empty. Does nothing if no group is affected.
+func replaceGroupMember(oldIDs []string, newID string) error {
+	groups, err := ListGroups()
+	if err != nil || len(groups) == 0 {
+		return err
+	}
+
+	return updateGroups(func(groups []Group) ([]Group, error) {
+		for i := range groups {
+			found := false
+			for _, id := range oldIDs {
+				if groups[i].HasMember(id) {
+					found = true
+					groups[i].Members = withoutMember(groups[i].Members, id)
+				}
+			}
+			if found && newID != "" && !groups[i].HasMember(newID) {
+				groups[i].Members = append(groups[i].Members, newID)
+			}
+		}
+		return groups, nil
+	})
+}
+
+func withoutMember(members []string, id string) []string {
+	kept := members[:0]
+	for _, member := range members {
+		if member != id {
+			kept = append(kept, member)
+		}
+	}
+	return kept
+}
+
+// updateGroups loads the groups, applies change and writes the result back.
+func updateGroups(change func([]Group) ([]Group, error)) error {
+	groups, err := ListGroups()
+	if err != nil {
+		return err
+	}
+
+	groups, err = change(groups)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(groups)
+	if err != nil {
+		return fmt.Errorf("failed to marshal groups: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(GetGroupsPath()), 0755); err != nil {
+		return fmt.Errorf("failed to create chime directory: %w", err)
+	}
+	if err := os.WriteFile(GetGroupsPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write groups: %w", err)
+	}
+	return nil
+}
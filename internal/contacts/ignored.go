@@ -0,0 +1,82 @@
+package contacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetIgnoredHandlesPath returns the file listing handles hidden from the unknown senders view
+// (~/.chime/ignored_handles.yml).
+func GetIgnoredHandlesPath() string {
+	return filepath.Join(filepath.Dir(GetContactsDir()), "ignored_handles.yml")
+}
+
+// ListIgnoredHandles returns the ignored handles, keyed by their normalised form.
+func ListIgnoredHandles() (map[string]bool, error) {
+	ignored := make(map[string]bool)
+
+	data, err := os.ReadFile(GetIgnoredHandlesPath())
+	if os.IsNotExist(err) {
+		return ignored, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignored handles: %w", err)
+	}
+
+	var handles []string
+	if err := yaml.Unmarshal(data, &handles); err != nil {
+		return nil, fmt.Errorf("failed to parse ignored handles: %w", err)
+	}
+	for _, handle := range handles {
+		ignored[NormalizeIdentifier(handle)] = true
+	}
+	return ignored, nil
+}
+
+// IgnoreHandles adds handles to the ignored list.
+func IgnoreHandles(handles ...string) error {
+	return updateIgnoredHandles(handles, true)
+}
+
+// UnignoreHandles removes handles from the ignored list.
+func UnignoreHandles(handles ...string) error {
+	return updateIgnoredHandles(handles, false)
+}
+
+func updateIgnoredHandles(handles []string, ignore bool) error {
+	ignored, err := ListIgnoredHandles()
+	if err != nil {
+		return err
+	}
+
+	for _, handle := range handles {
+		if ignore {
+			ignored[NormalizeIdentifier(handle)] = true
+		} else {
+			delete(ignored, NormalizeIdentifier(handle))
+		}
+	}
+
+	list := make([]string, 0, len(ignored))
+	for handle := range ignored {
+		list = append(list, handle)
+	}
+	sort.Strings(list)
+
+	data, err := yaml.Marshal(list)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ignored handles: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(GetIgnoredHandlesPath()), 0755); err != nil {
+		return fmt.Errorf("failed to create chime directory: %w", err)
+	}
+	if err := os.WriteFile(GetIgnoredHandlesPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write ignored handles: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package contacts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ImportChange is one contact an import will create or update.
+type ImportChange struct {
+	// Contact is the contact as it will be saved.
+	Contact Contact
+	// Existing is the saved contact being merged into, or nil for a new contact.
+	Existing *Contact
+}
+
+// ImportPlan lists what importing a set of contacts would do, so it can be previewed
+// before anything is written.
+type ImportPlan struct {
+	Changes []ImportChange
+	// Unchanged counts incoming contacts that add nothing to the contact they match.
+	Unchanged int
+	// Warnings describe phone numbers and emails left out of the import because they are
+	// malformed or belong to a different contact.
+	Warnings []string
+}
+
+// Created returns the number of new contacts in the plan.
+func (p ImportPlan) Created() int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Existing == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Merged returns the number of existing contacts the plan updates.
+func (p ImportPlan) Merged() int {
+	return len(p.Changes) - p.Created()
+}
+
+// PlanImport matches each incoming contact against the saved contacts, and against the
+// incoming contacts before it, by normalised phone number or email and then by name. Matches
+// are merged with MergeContacts, keeping the saved contact's name; the rest are created.
+// Malformed phone numbers and emails, and those of a contact other than the match, are
+// left out and listed in the plan's Warnings.
+func PlanImport(incoming []Contact) (ImportPlan, error) {
+	existing, err := ListContacts()
+	if err != nil {
+		return ImportPlan{}, err
+	}
+
+	var plan ImportPlan
+	working := make([]Contact, len(existing))
+	copy(working, existing)
+	changeFor := make(map[int]int)
+	byIdentifier := make(map[string]int)
+	byName := make(map[string]int)
+
+	index := func(i int) {
+		contact := working[i]
+		for _, identifier := range append(append([]string{}, contact.PhoneNumbers...), contact.Emails...) {
+			if key := contact.Canonical(identifier); key != "" {
+				if _, ok := byIdentifier[key]; !ok {
+					byIdentifier[key] = i
+				}
+			}
+		}
+		if key := nameKey(contact.Name); key != "" {
+			if _, ok := byName[key]; !ok {
+				byName[key] = i
+			}
+		}
+	}
+	for i := range working {
+		index(i)
+	}
+
+	for _, contact := range incoming {
+		contact, dropped := dropInvalidIdentifiers(contact)
+		plan.Warnings = append(plan.Warnings, dropped...)
+
+		match := -1
+		for _, identifier := range append(append([]string{}, contact.PhoneNumbers...), contact.Emails...) {
+			if i, ok := byIdentifier[NormalizeIdentifier(identifier)]; ok {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			if i, ok := byName[nameKey(contact.Name)]; ok {
+				match = i
+			}
+		}
+
+		unowned := func(identifiers []string) []string {
+			var kept []string
+			for _, identifier := range identifiers {
+				if i, ok := byIdentifier[NormalizeIdentifier(identifier)]; ok && i != match {
+					plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: skipped %s, which belongs to %s", contact.Name, identifier, working[i].Name))
+					continue
+				}
+				kept = append(kept, identifier)
+			}
+			return kept
+		}
+		contact.PhoneNumbers = unowned(contact.PhoneNumbers)
+		contact.Emails = unowned(contact.Emails)
+
+		if match < 0 {
+			contact.ID = NewContactID()
+			working = append(working, contact)
+			match = len(working) - 1
+			changeFor[match] = len(plan.Changes)
+			plan.Changes = append(plan.Changes, ImportChange{Contact: contact})
+			index(match)
+			continue
+		}
+
+		merged := MergeContacts(working[match], contact)
+		if sameContact(merged, working[match]) {
+			plan.Unchanged++
+			continue
+		}
+		working[match] = merged
+		index(match)
+
+		if c, ok := changeFor[match]; ok {
+			plan.Changes[c].Contact = merged
+			continue
+		}
+		before := existing[match]
+		changeFor[match] = len(plan.Changes)
+		plan.Changes = append(plan.Changes, ImportChange{Contact: merged, Existing: &before})
+	}
+
+	return plan, nil
+}
+
+// ApplyImportPlan saves every contact in the plan.
+func ApplyImportPlan(plan ImportPlan) error {
+	for _, change := range plan.Changes {
+		if err := saveContact(change.Contact, saveOptions{ownersChecked: true}); err != nil {
+			return fmt.Errorf("failed to import %s: %w", change.Contact.Name, err)
+		}
+	}
+	return nil
+}
+
+// ImportSummary describes what a merge adds to an existing contact, e.g.
+// "+447700900123, #family, birthday".
+func ImportSummary(before, after Contact) string {
+	var added []string
+	for _, phone := range after.PhoneNumbers {
+		if !containsIdentifier(before.PhoneNumbers, phone) {
+			added = append(added, phone)
+		}
+	}
+	for _, email := range after.Emails {
+		if !containsIdentifier(before.Emails, email) {
+			added = append(added, email)
+		}
+	}
+	for _, tag := range after.Tags {
+		if !before.HasTag(tag) {
+			added = append(added, "#"+tag)
+		}
+	}
+
+	fields := []struct{ name, before, after string }{
+		{"nickname", before.Nickname, after.Nickname},
+		{"organisation", before.Organization, after.Organization},
+		{"birthday", before.Birthday, after.Birthday},
+		{"notes", before.Notes, after.Notes},
+	}
+	for _, field := range fields {
+		if field.before != field.after {
+			added = append(added, field.name)
+		}
+	}
+	if after.Favorite && !before.Favorite {
+		added = append(added, "favourite")
+	}
+	return strings.Join(added, ", ")
+}
+
+func containsIdentifier(identifiers []string, identifier string) bool {
+	key := NormalizeIdentifier(identifier)
+	for _, existing := range identifiers {
+		if NormalizeIdentifier(existing) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// sameContact reports whether a and b hold the same data, treating nil and empty lists alike.
+func sameContact(a, b Contact) bool {
+	normalize := func(c Contact) Contact {
+		if len(c.PhoneNumbers) == 0 {
+			c.PhoneNumbers = nil
+		}
+		if len(c.Emails) == 0 {
+			c.Emails = nil
+		}
+		if len(c.Tags) == 0 {
+			c.Tags = nil
+		}
+		if len(c.Labels) == 0 {
+			c.Labels = nil
+		}
+		return c
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
@@ -0,0 +1,126 @@
+package contacts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/saravenpi/chime/internal/config"
+)
+
+func TestPlanImport(t *testing.T) {
+	previous := config.Current()
+	t.Cleanup(func() {
+		config.Set(previous)
+		InvalidateCache()
+	})
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	cfg.ContactsBackend = "yaml"
+	config.Set(cfg)
+	setRegionForTest(t, "GB")
+
+	saved := []Contact{
+		{Name: "Alice Martin", PhoneNumbers: []string{"+447700900123"}, Emails: []string{"alice@example.com"}},
+		{Name: "Bob Smith", PhoneNumbers: []string{"+14155550100"}, Emails: []string{"bob@example.com"}},
+	}
+	for _, contact := range saved {
+		if err := SaveContact(contact); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	incoming := []Contact{
+		// Matches Alice by a differently written phone number.
+		{Name: "Ali", PhoneNumbers: []string{"07700 900123"}, Tags: []string{"family"}},
+		// Matches Bob by name.
+		{Name: "Smith, Bob", Emails: []string{"robert@example.com"}},
+		// Matches Bob by phone; Alice's email is left out.
+		{Name: "Bobby", PhoneNumbers: []string{"+1 415 555 0100"}, Emails: []string{"Alice@Example.com"}},
+		// Two new rows for the same person become one contact.
+		{Name: "Erin Lee", Emails: []string{"erin@example.com"}},
+		{Name: "E. Lee", Emails: []string{"ERIN@example.com"}, Nickname: "Ez"},
+		// A malformed phone number is dropped.
+		{Name: "Frank Moore", PhoneNumbers: []string{"12"}},
+	}
+
+	plan, err := PlanImport(incoming)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := plan.Created(); got != 2 {
+		t.Errorf("Created() = %d, want 2", got)
+	}
+	if got := plan.Merged(); got != 2 {
+		t.Errorf("Merged() = %d, want 2", got)
+	}
+	if plan.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", plan.Unchanged)
+	}
+	if len(plan.Changes) != 4 {
+		t.Fatalf("plan has %d changes, want 4: %#v", len(plan.Changes), plan.Changes)
+	}
+
+	alice, bob, erin, frank := plan.Changes[0], plan.Changes[1], plan.Changes[2], plan.Changes[3]
+
+	if alice.Existing == nil || alice.Existing.Name != "Alice Martin" {
+		t.Errorf("first change merges into %v, want Alice Martin", alice.Existing)
+	} else {
+		if alice.Contact.ID != alice.Existing.ID || alice.Contact.Name != "Alice Martin" {
+			t.Errorf("Alice merged as %q (%s), want the saved name and ID", alice.Contact.Name, alice.Contact.ID)
+		}
+		if got := ImportSummary(*alice.Existing, alice.Contact); got != "#family" {
+			t.Errorf("ImportSummary(Alice) = %q, want #family", got)
+		}
+	}
+
+	if bob.Existing == nil || bob.Existing.Name != "Bob Smith" {
+		t.Errorf("second change merges into %v, want Bob Smith", bob.Existing)
+	} else {
+		if want := []string{"bob@example.com", "robert@example.com"}; !reflect.DeepEqual(bob.Contact.Emails, want) {
+			t.Errorf("Bob's emails = %q, want %q", bob.Contact.Emails, want)
+		}
+		if got := ImportSummary(*bob.Existing, bob.Contact); got != "robert@example.com" {
+			t.Errorf("ImportSummary(Bob) = %q, want robert@example.com", got)
+		}
+	}
+
+	if erin.Existing != nil || erin.Contact.Name != "Erin Lee" || erin.Contact.Nickname != "Ez" ||
+		!reflect.DeepEqual(erin.Contact.Emails, []string{"erin@example.com"}) || erin.Contact.ID == "" {
+		t.Errorf("third change = %#v, want a new Erin Lee with nickname Ez and one email", erin)
+	}
+	if frank.Existing != nil || frank.Contact.Name != "Frank Moore" || len(frank.Contact.PhoneNumbers) != 0 {
+		t.Errorf("fourth change = %#v, want a new Frank Moore without phone numbers", frank)
+	}
+
+	if len(plan.Warnings) != 2 ||
+		plan.Warnings[0] != "Bobby: skipped Alice@Example.com, which belongs to Alice Martin" ||
+		!strings.HasPrefix(plan.Warnings[1], "Frank Moore: skipped ") {
+		t.Errorf("Warnings = %q, want Alice's email skipped for Bobby and Frank's phone dropped", plan.Warnings)
+	}
+
+	listed, err := ListContacts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("PlanImport saved contacts: %d listed, want 2", len(listed))
+	}
+
+	if err := ApplyImportPlan(plan); err != nil {
+		t.Fatal(err)
+	}
+	listed, err = ListContacts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 4 {
+		t.Errorf("ApplyImportPlan left %d contacts, want 4", len(listed))
+	}
+	for _, contact := range listed {
+		if contact.Name == "Alice Martin" && !contact.HasTag("family") {
+			t.Errorf("Alice Martin saved without the imported tag: %#v", contact)
+		}
+	}
+}
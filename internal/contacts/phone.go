@@ -0,0 +1,215 @@
+package contacts
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// phoneRegion describes how numbers are dialled nationally in a country.
+type phoneRegion struct {
+	callingCode string
+	// trunkPrefix is dropped from national numbers when converting to E.164 (e.g. "0" in the UK).
+	// It is empty where the leading digit is part of the number, as in Italy.
+	trunkPrefix string
+	// internationalPrefix replaces "+" when dialling abroad from this region (e.g. "00", "011").
+	internationalPrefix string
+}
+
+var phoneRegions = map[string]phoneRegion{
+	"US": {"1", "1", "011"},
+	"CA": {"1", "1", "011"},
+	"GB": {"44", "0", "00"},
+	"IE": {"353", "0", "00"},
+	"FR": {"33", "0", "00"},
+	"DE": {"49", "0", "00"},
+	"AT": {"43", "0", "00"},
+	"CH": {"41", "0", "00"},
+	"BE": {"32", "0", "00"},
+	"NL": {"31", "0", "00"},
+	"LU": {"352", "", "00"},
+	"ES": {"34", "", "00"},
+	"PT": {"351", "", "00"},
+	"IT": {"39", "", "00"},
+	"DK": {"45", "", "00"},
+	"NO": {"47", "", "00"},
+	"SE": {"46", "0", "00"},
+	"FI": {"358", "0", "00"},
+	"PL": {"48", "", "00"},
+	"CZ": {"420", "", "00"},
+	"GR": {"30", "", "00"},
+	"TR": {"90", "0", "00"},
+	"RU": {"7", "8", "810"},
+	"UA": {"380", "0", "00"},
+	"IL": {"972", "0", "00"},
+	"AE": {"971", "0", "00"},
+	"ZA": {"27", "0", "00"},
+	"NG": {"234", "0", "009"},
+	"EG": {"20", "0", "00"},
+	"IN": {"91", "0", "00"},
+	"CN": {"86", "0", "00"},
+	"HK": {"852", "", "001"},
+	"SG": {"65", "", "000"},
+	"JP": {"81", "0", "010"},
+	"KR": {"82", "0", "001"},
+	"AU": {"61", "0", "0011"},
+	"NZ": {"64", "0", "00"},
+	"BR": {"55", "0", "00"},
+	"MX": {"52", "", "00"},
+	"AR": {"54", "0", "00"},
+	"CL": {"56", "", "00"},
+	"CO": {"57", "", "00"},
+}
+
+// minNationalNumberLength is the shortest number treated as a full phone number.
+// Anything shorter (SMS short codes, for example) is left as plain digits.
+const minNationalNumberLength = 7
+
+var (
+	defaultRegion      = detectRegion()
+	defaultRegionMutex sync.RWMutex
+)
+
+// DefaultRegion returns the region used to interpret phone numbers written without a
+// country code. It defaults to the country in $LC_ALL or $LANG, or "US".
+func DefaultRegion() string {
+	defaultRegionMutex.RLock()
+	defer defaultRegionMutex.RUnlock()
+	return defaultRegion
+}
+
+// SetDefaultRegion sets the ISO 3166 region code (e.g. "GB") used for national numbers.
+func SetDefaultRegion(region string) error {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if _, ok := phoneRegions[region]; !ok {
+		return fmt.Errorf("unsupported phone region %q (supported: %s)", region, strings.Join(SupportedRegions(), ", "))
+	}
+
+	defaultRegionMutex.Lock()
+	defaultRegion = region
+	defaultRegionMutex.Unlock()
+
+	InvalidateCache()
+	addressBookCacheMutex.Lock()
+	addressBookLookupMap = nil
+	addressBookCacheMutex.Unlock()
+	return nil
+}
+
+// SupportedRegions returns the region codes NormalizePhoneNumber understands, sorted.
+func SupportedRegions() []string {
+	regions := make([]string, 0, len(phoneRegions))
+	for region := range phoneRegions {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// NormalizePhoneNumber converts a phone number to E.164 (e.g. "+447700900123").
+// Numbers with a "+" or the region's international dialling prefix keep their country code;
+// national numbers have the region's trunk prefix removed and its calling code added.
+// Short codes and numbers for unknown regions are returned as bare digits.
+func NormalizePhoneNumber(raw, region string) string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(strings.ToLower(raw), "tel:")
+	if cut := strings.IndexAny(raw, "x;,"); cut != -1 {
+		raw = raw[:cut]
+	}
+	// "+44 (0)20 ..." marks a trunk prefix that must not be dialled with the country code.
+	raw = strings.ReplaceAll(raw, "(0)", "")
+
+	hasPlus := false
+	digits := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case c == '+' && len(digits) == 0:
+			hasPlus = true
+		}
+	}
+	number := string(digits)
+
+	if number == "" {
+		return ""
+	}
+	if hasPlus {
+		return "+" + number
+	}
+
+	info, ok := phoneRegions[strings.ToUpper(region)]
+	if !ok {
+		return number
+	}
+
+	if strings.HasPrefix(number, info.internationalPrefix) && len(number) > len(info.internationalPrefix)+minNationalNumberLength {
+		return "+" + number[len(info.internationalPrefix):]
+	}
+
+	if len(number) < minNationalNumberLength {
+		return number
+	}
+
+	if info.callingCode == "1" {
+		switch {
+		case len(number) == 10:
+			return "+1" + number
+		case len(number) == 11 && strings.HasPrefix(number, "1"):
+			return "+" + number
+		}
+		return number
+	}
+
+	if info.trunkPrefix != "" && strings.HasPrefix(number, info.trunkPrefix) {
+		return "+" + info.callingCode + number[len(info.trunkPrefix):]
+	}
+
+	return "+" + info.callingCode + number
+}
+
+// ValidatePhoneNumber checks that raw only uses the characters found in written phone numbers
+// and has enough digits to be a full number or an SMS short code.
+func ValidatePhoneNumber(raw string) error {
+	raw = strings.TrimSpace(raw)
+	digits := 0
+	for i, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" -.()/", r):
+		default:
+			return fmt.Errorf("invalid phone number %q: unexpected %q", raw, r)
+		}
+	}
+
+	if digits < 3 {
+		return fmt.Errorf("invalid phone number %q: too few digits", raw)
+	}
+	if digits > 15 {
+		return fmt.Errorf("invalid phone number %q: too many digits", raw)
+	}
+	return nil
+}
+
+// detectRegion guesses the default region from the locale environment (e.g. "en_GB.UTF-8").
+func detectRegion() string {
+	for _, key := range []string{"LC_ALL", "LC_TELEPHONE", "LANG"} {
+		locale := os.Getenv(key)
+		if locale == "" {
+			continue
+		}
+		locale = strings.SplitN(locale, ".", 2)[0]
+		if _, country, found := strings.Cut(locale, "_"); found {
+			country = strings.ToUpper(country)
+			if _, ok := phoneRegions[country]; ok {
+				return country
+			}
+		}
+	}
+	return "US"
+}
@@ -0,0 +1,156 @@
+package contacts
+
+import (
+	"strings"
+	"testing"
+)
+
+// setRegionForTest sets the default phone region for the rest of the test and restores the
+// previous one when it ends.
+func setRegionForTest(t *testing.T, region string) {
+	t.Helper()
+	previous := DefaultRegion()
+	if err := SetDefaultRegion(region); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := SetDefaultRegion(previous); err != nil {
+			t.Errorf("failed to restore phone region %q: %v", previous, err)
+		}
+	})
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		raw    string
+		region string
+		want   string
+	}{
+		// United Kingdom
+		{"07700 900123", "GB", "+447700900123"},
+		{"020 7946 0018", "GB", "+442079460018"},
+		{"+44 7700 900123", "GB", "+447700900123"},
+		{"+44 (0)20 7946 0018", "GB", "+442079460018"},
+		{"0044 20 7946 0018", "GB", "+442079460018"},
+		{"+44 7700 900123", "US", "+447700900123"},
+
+		// France
+		{"06 12 34 56 78", "FR", "+33612345678"},
+		{"06.12.34.56.78", "FR", "+33612345678"},
+		{"+33 6 12 34 56 78", "FR", "+33612345678"},
+		{"0033 6 12 34 56 78", "FR", "+33612345678"},
+
+		// Germany
+		{"030 901820", "DE", "+4930901820"},
+		{"0151 23456789", "DE", "+4915123456789"},
+		{"+49 (0)30 901820", "DE", "+4930901820"},
+		{"0049 151 23456789", "DE", "+4915123456789"},
+
+		// United States
+		{"(415) 555-0100", "US", "+14155550100"},
+		{"415.555.0100", "US", "+14155550100"},
+		{"1-415-555-0100", "US", "+14155550100"},
+		{"+1 415 555 0100", "US", "+14155550100"},
+		{"415 555 0100", "CA", "+14155550100"},
+		{"011 44 7700 900123", "US", "+447700900123"},
+		{"(415) 555-0100", "GB", "+444155550100"},
+
+		// Regions without a trunk prefix keep the leading zero.
+		{"06 1234 5678", "IT", "+390612345678"},
+
+		// Extensions and URIs
+		{"tel:+1-415-555-0100", "US", "+14155550100"},
+		{"(415) 555-0100 x123", "US", "+14155550100"},
+		{"+44 20 7946 0018;ext=4", "GB", "+442079460018"},
+
+		// Short codes, unknown regions and things that aren't numbers
+		{"12345", "GB", "12345"},
+		{"262966", "US", "262966"},
+		{"00123", "GB", "00123"},
+		{"07700 900123", "ZZ", "07700900123"},
+		{"415555010", "US", "415555010"},
+		{"", "GB", ""},
+		{"   ", "US", ""},
+		{"no digits", "US", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizePhoneNumber(tt.raw, tt.region); got != tt.want {
+			t.Errorf("NormalizePhoneNumber(%q, %q) = %q, want %q", tt.raw, tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeIdentifier(t *testing.T) {
+	setRegionForTest(t, "GB")
+
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"07700 900123", "+447700900123"},
+		{" +33 6 12 34 56 78 ", "+33612345678"},
+		{"Alice.Martin@Example.COM", "alice.martin@example.com"},
+		{"  bob@example.com ", "bob@example.com"},
+		{"61234", "61234"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeIdentifier(tt.raw); got != tt.want {
+			t.Errorf("NormalizeIdentifier(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePhoneNumber(t *testing.T) {
+	tests := []struct {
+		raw     string
+		wantErr string
+	}{
+		{"+44 7700 900123", ""},
+		{"(415) 555-0100", ""},
+		{"06.12.34.56.78", ""},
+		{"030/901820", ""},
+		{"123", ""},
+		{"+123456789012345", ""},
+		{"  07700 900123  ", ""},
+
+		{"", "too few digits"},
+		{"12", "too few digits"},
+		{"+1 ( ) -", "too few digits"},
+		{"1234567890123456", "too many digits"},
+		{"+44 7700 900123 0000", "too many digits"},
+		{"0800 FLOWERS", "unexpected 'F'"},
+		{"555-abc", "unexpected 'a'"},
+		{"07700+900123", "unexpected '+'"},
+		{"alice@example.com", "unexpected 'a'"},
+		{"415 555 0100 x12", "unexpected 'x'"},
+	}
+
+	for _, tt := range tests {
+		err := ValidatePhoneNumber(tt.raw)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("ValidatePhoneNumber(%q) = %v, want nil", tt.raw, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("ValidatePhoneNumber(%q) = %v, want error containing %q", tt.raw, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSetDefaultRegion(t *testing.T) {
+	setRegionForTest(t, DefaultRegion())
+
+	if err := SetDefaultRegion(" fr "); err != nil {
+		t.Fatalf("SetDefaultRegion(\" fr \") = %v", err)
+	}
+	if got := DefaultRegion(); got != "FR" {
+		t.Errorf("DefaultRegion() = %q, want FR", got)
+	}
+	if err := SetDefaultRegion("ZZ"); err == nil {
+		t.Error("SetDefaultRegion(\"ZZ\") succeeded, want an error")
+	}
+	if got := DefaultRegion(); got != "FR" {
+		t.Errorf("DefaultRegion() after a failed set = %q, want FR", got)
+	}
+}
@@ -0,0 +1,172 @@
+package contacts
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Store persists contacts. SaveContact, LoadContact, DeleteContact and ListContacts go
+// through the store for the active backend; callers validate IDs before calling it.
+type Store interface {
+	// List returns every contact, in no particular order.
+	List() ([]Contact, error)
+	Load(id string) (*Contact, error)
+	Save(contact Contact) error
+	Delete(id string) error
+	Close() error
+}
+
+// identifierIndex is implemented by stores that can find a contact by normalised phone
+// number or email without listing every contact.
+type identifierIndex interface {
+	FindByIdentifier(normalized string) (*Contact, error)
+}
+
+// Contact storage backends.
+const (
+	BackendYAML   = "yaml"
+	BackendSQLite = "sqlite"
+)
+
+var (
+	storeMutex   sync.Mutex
+	activeStore  Store
+	storeBackend string
+)
+
+// SetBackend chooses where contacts are stored: "yaml" for one file per contact in
+// ~/.chime/contacts, "sqlite" for ~/.chime/contacts.db, or "" to use SQLite when that
+// database exists and YAML otherwise.
+func SetBackend(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name != "" && name != BackendYAML && name != BackendSQLite {
+		return fmt.Errorf("unknown contacts backend %q (available: %s, %s)", name, BackendYAML, BackendSQLite)
+	}
+
+	storeMutex.Lock()
+	storeBackend = name
+	if activeStore != nil {
+		activeStore.Close()
+		activeStore = nil
+	}
+	storeMutex.Unlock()
+
+	InvalidateCache()
+	return nil
+}
+
+// Backend returns the backend contacts are stored in, resolving automatic selection.
+func Backend() string {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+	return resolveBackend()
+}
+
+func resolveBackend() string {
+	if storeBackend != "" {
+		return storeBackend
+	}
+	if _, err := os.Stat(GetSQLitePath()); err == nil {
+		return BackendSQLite
+	}
+	return BackendYAML
+}
+
+// OpenStore opens the store for a backend. The caller must close it.
+func OpenStore(backend string) (Store, error) {
+	switch backend {
+	case BackendYAML:
+		return yamlStore{}, nil
+	case BackendSQLite:
+		return openSQLiteStore(GetSQLitePath())
+	default:
+		return nil, fmt.Errorf("unknown contacts backend %q (available: %s, %s)", backend, BackendYAML, BackendSQLite)
+	}
+}
+
+// currentStore returns the store for the active backend, opening it on first use.
+func currentStore() (Store, error) {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+
+	if activeStore != nil {
+		return activeStore, nil
+	}
+
+	store, err := OpenStore(resolveBackend())
+	if err != nil {
+		return nil, err
+	}
+	activeStore = store
+	return store, nil
+}
+
+// MigrateContacts copies every contact from one backend to the other, keeping their IDs,
+// and switches to the target backend. Contacts left in the target by an earlier migration
+// that no longer exist in the source are removed, so deleted contacts don't reappear.
+// Migrating to YAML renames ~/.chime/contacts.db to contacts.db.bak so it isn't picked up
+// again; migrating to SQLite leaves the YAML files in place as a backup. Returns the number
+// of contacts copied.
+func MigrateContacts(from, to string) (int, error) {
+	if from == to {
+		return 0, fmt.Errorf("contacts are already stored in %s", to)
+	}
+
+	source, err := OpenStore(from)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	contacts, err := source.List()
+	if err != nil {
+		return 0, err
+	}
+
+	target, err := OpenStore(to)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := make(map[string]bool, len(contacts))
+	for i, contact := range contacts {
+		if err := target.Save(contact); err != nil {
+			target.Close()
+			return i, fmt.Errorf("failed to migrate %s: %w", contact.Name, err)
+		}
+		migrated[contact.ID] = true
+	}
+
+	stale, err := target.List()
+	if err != nil {
+		target.Close()
+		return len(contacts), err
+	}
+	for _, contact := range stale {
+		if !migrated[contact.ID] {
+			if err := target.Delete(contact.ID); err != nil {
+				target.Close()
+				return len(contacts), fmt.Errorf("failed to remove %s left from an earlier migration: %w", contact.Name, err)
+			}
+		}
+	}
+	if err := target.Close(); err != nil {
+		return len(contacts), fmt.Errorf("failed to close %s store: %w", to, err)
+	}
+
+	if from == BackendSQLite {
+		if err := SetBackend(BackendYAML); err != nil {
+			return len(contacts), err
+		}
+		if err := os.Rename(GetSQLitePath(), GetSQLitePath()+".bak"); err != nil {
+			return len(contacts), fmt.Errorf("contacts migrated but failed to move the old database aside: %w", err)
+		}
+	}
+
+	if err := SetBackend(to); err != nil {
+		return len(contacts), err
+	}
+	return len(contacts), nil
+}
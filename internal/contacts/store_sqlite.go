@@ -0,0 +1,195 @@
+package contacts
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+	"gopkg.in/yaml.v3"
+)
+
+// sqliteStore keeps contacts in a single SQLite database. Each contact is stored as the
+// same YAML document the file store writes, next to an index of its normalised phone
+// numbers and emails for lookups that don't load every contact.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+const sqliteSchema = `
+	CREATE TABLE IF NOT EXISTS contacts (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		favorite INTEGER NOT NULL DEFAULT 0,
+		data TEXT NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS contact_identifiers (
+		normalized TEXT NOT NULL,
+		contact_id TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS contact_identifiers_normalized ON contact_identifiers (normalized);
+	CREATE INDEX IF NOT EXISTS contact_identifiers_contact_id ON contact_identifiers (contact_id);
+`
+
+// GetSQLitePath returns the path of the SQLite contacts database (~/.chime/contacts.db).
+func GetSQLitePath() string {
+	return filepath.Join(filepath.Dir(GetContactsDir()), "contacts.db")
+}
+
+// openSQLiteStore opens or creates the database at path.
+func openSQLiteStore(path string) (*sqliteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create chime directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open contacts database: %w", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create contacts database: %w", err)
+	}
+
+	return &sqliteStore{db: db}, nil
+}
+
+// insertIdentifiers indexes the contact's phone numbers and emails by their canonical form.
+// Like lookups through ListContacts, this uses the form recorded when the contact was saved,
+// so changing the default region doesn't move numbers already entered.
+func insertIdentifiers(tx *sql.Tx, contact Contact) error {
+	for _, identifier := range append(append([]string{}, contact.PhoneNumbers...), contact.Emails...) {
+		normalized := contact.Canonical(identifier)
+		if normalized == "" {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO contact_identifiers (normalized, contact_id) VALUES (?, ?)`, normalized, contact.ID); err != nil {
+			return fmt.Errorf("failed to index contact: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *sqliteStore) Save(contact Contact) error {
+	data, err := yaml.Marshal(&contact)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contact: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save contact: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT OR REPLACE INTO contacts (id, name, favorite, data) VALUES (?, ?, ?, ?)`,
+		contact.ID, contact.Name, contact.Favorite, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save contact: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM contact_identifiers WHERE contact_id = ?`, contact.ID); err != nil {
+		return fmt.Errorf("failed to save contact: %w", err)
+	}
+	if err := insertIdentifiers(tx, contact); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save contact: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Load(id string) (*Contact, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM contacts WHERE id = ?`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("contact not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contact: %w", err)
+	}
+	return parseStoredContact(id, data)
+}
+
+func (s *sqliteStore) Delete(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM contacts WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("contact not found: %s", id)
+	}
+	if _, err := tx.Exec(`DELETE FROM contact_identifiers WHERE contact_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) List() ([]Contact, error) {
+	rows, err := s.db.Query(`SELECT id, data FROM contacts`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []Contact
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			continue
+		}
+		contact, err := parseStoredContact(id, data)
+		if err != nil {
+			continue
+		}
+		contacts = append(contacts, *contact)
+	}
+
+	return contacts, rows.Err()
+}
+
+// FindByIdentifier returns the contact with the normalised phone number or email, preferring
+// favourites and then the first by name, as ListContacts does. Returns nil if none has it.
+func (s *sqliteStore) FindByIdentifier(normalized string) (*Contact, error) {
+	var id, data string
+	err := s.db.QueryRow(`
+		SELECT c.id, c.data
+		FROM contact_identifiers i
+		JOIN contacts c ON c.id = i.contact_id
+		WHERE i.normalized = ?
+		ORDER BY c.favorite DESC, c.name COLLATE NOCASE
+		LIMIT 1
+	`, normalized).Scan(&id, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up contact: %w", err)
+	}
+	return parseStoredContact(id, data)
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
+
+func parseStoredContact(id, data string) (*Contact, error) {
+	var contact Contact
+	if err := yaml.Unmarshal([]byte(data), &contact); err != nil {
+		return nil, fmt.Errorf("failed to parse contact %s: %w", id, err)
+	}
+	contact.ID = id
+	return &contact, nil
+}
@@ -0,0 +1,210 @@
+package contacts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/saravenpi/chime/internal/config"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	setRegionForTest(t, "GB")
+
+	store, err := openSQLiteStore(filepath.Join(t.TempDir(), "contacts.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	alice := Contact{
+		ID:           NewContactID(),
+		Name:         "Alice Martin",
+		PhoneNumbers: []string{"07700 900123"},
+		Emails:       []string{"alice@example.com"},
+		Labels:       map[string]string{"07700 900123": "mobile"},
+		Normalized:   map[string]string{"07700 900123": "+447700900123"},
+		Tags:         []string{"family"},
+		Birthday:     "1990-01-15",
+	}
+	shared := Contact{
+		ID:       NewContactID(),
+		Name:     "Zoe Martin",
+		Emails:   []string{"Alice@Example.com"},
+		Favorite: true,
+	}
+	for _, contact := range []Contact{alice, shared} {
+		if err := store.Save(contact); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := store.Load(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*loaded, alice) {
+		t.Errorf("Load() =\n%#v\nwant\n%#v", *loaded, alice)
+	}
+	listed, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 {
+		t.Errorf("List() returned %d contacts, want 2", len(listed))
+	}
+
+	find := func(normalized string) string {
+		t.Helper()
+		contact, err := store.FindByIdentifier(normalized)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contact == nil {
+			return ""
+		}
+		return contact.Name
+	}
+	tests := []struct {
+		normalized string
+		want       string
+	}{
+		{"+447700900123", "Alice Martin"},
+		{"alice@example.com", "Zoe Martin"},
+		{"+442079460018", ""},
+	}
+	for _, tt := range tests {
+		if got := find(tt.normalized); got != tt.want {
+			t.Errorf("FindByIdentifier(%q) = %q, want %q", tt.normalized, got, tt.want)
+		}
+	}
+
+	// Saving again replaces the contact's index entries.
+	alice.PhoneNumbers = []string{"020 7946 0018"}
+	alice.Normalized = nil
+	if err := store.Save(alice); err != nil {
+		t.Fatal(err)
+	}
+	if got := find("+447700900123"); got != "" {
+		t.Errorf("FindByIdentifier(old phone) = %q after it was removed, want none", got)
+	}
+	if got := find("+442079460018"); got != "Alice Martin" {
+		t.Errorf("FindByIdentifier(new phone) = %q, want Alice Martin", got)
+	}
+
+	if err := store.Delete(shared.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := find("alice@example.com"); got != "Alice Martin" {
+		t.Errorf("FindByIdentifier(shared email) = %q after deleting Zoe, want Alice Martin", got)
+	}
+	if _, err := store.Load(shared.ID); err == nil {
+		t.Error("Load() of a deleted contact succeeded, want an error")
+	}
+	if err := store.Delete(shared.ID); err == nil {
+		t.Error("Delete() of a deleted contact succeeded, want an error")
+	}
+}
+
+func TestMigrateContacts(t *testing.T) {
+	previous := config.Current()
+	storeMutex.Lock()
+	previousBackend := storeBackend
+	storeMutex.Unlock()
+	t.Cleanup(func() {
+		config.Set(previous)
+		SetBackend(previousBackend)
+	})
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	config.Set(cfg)
+	if err := SetBackend(BackendYAML); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(t *testing.T, store Store) []string {
+		t.Helper()
+		listed, err := store.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, contact := range listed {
+			names = append(names, contact.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	alice := Contact{ID: NewContactID(), Name: "Alice Martin", PhoneNumbers: []string{"+447700900123"}}
+	bob := Contact{ID: NewContactID(), Name: "Bob Smith", Emails: []string{"bob@example.com"}}
+	for _, contact := range []Contact{alice, bob} {
+		if err := (yamlStore{}).Save(contact); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := MigrateContacts(BackendYAML, BackendYAML); err == nil {
+		t.Error("MigrateContacts(yaml, yaml) succeeded, want an error")
+	}
+
+	n, err := MigrateContacts(BackendYAML, BackendSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("MigrateContacts(yaml, sqlite) = %d, want 2", n)
+	}
+	if got := Backend(); got != BackendSQLite {
+		t.Errorf("Backend() = %q after migrating to SQLite", got)
+	}
+	if _, err := os.Stat(getContactFilePath(alice.ID)); err != nil {
+		t.Errorf("YAML files not kept as a backup: %v", err)
+	}
+	loaded, err := LoadContact(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Name != alice.Name || !reflect.DeepEqual(loaded.PhoneNumbers, alice.PhoneNumbers) {
+		t.Errorf("LoadContact() from SQLite = %#v, want %#v", loaded, alice)
+	}
+
+	// Changes made while on SQLite carry back, and Bob, deleted there, isn't restored from
+	// the YAML files left behind.
+	if err := DeleteContact(bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveContact(Contact{Name: "Carol Jones", Emails: []string{"carol@example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err = MigrateContacts(BackendSQLite, BackendYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("MigrateContacts(sqlite, yaml) = %d, want 2", n)
+	}
+	if got := Backend(); got != BackendYAML {
+		t.Errorf("Backend() = %q after migrating to YAML", got)
+	}
+	if _, err := os.Stat(GetSQLitePath()); !os.IsNotExist(err) {
+		t.Errorf("contacts.db still in place after migrating to YAML: %v", err)
+	}
+	if _, err := os.Stat(GetSQLitePath() + ".bak"); err != nil {
+		t.Errorf("contacts.db not renamed to contacts.db.bak: %v", err)
+	}
+	if got, want := names(t, yamlStore{}), []string{"Alice Martin", "Carol Jones"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("YAML contacts after migrating back = %q, want %q", got, want)
+	}
+
+	// With the database moved aside, automatic selection stays on YAML.
+	if err := SetBackend(""); err != nil {
+		t.Fatal(err)
+	}
+	if got := Backend(); got != BackendYAML {
+		t.Errorf("Backend() = %q with contacts.db.bak only, want yaml", got)
+	}
+}
@@ -0,0 +1,154 @@
+package contacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlStore keeps one YAML file per contact in ~/.chime/contacts.
+type yamlStore struct{}
+
+// ensureContactsDir creates the contacts directory if it doesn't exist.
+func ensureContactsDir() error {
+	dir := GetContactsDir()
+	return os.MkdirAll(dir, 0755)
+}
+
+// getContactFilePath returns the full path to a contact's YAML file.
+func getContactFilePath(id string) string {
+	return filepath.Join(GetContactsDir(), id+".yml")
+}
+
+func (yamlStore) Save(contact Contact) error {
+	if err := ensureContactsDir(); err != nil {
+		return fmt.Errorf("failed to create contacts directory: %w", err)
+	}
+	return writeContactFile(getContactFilePath(contact.ID), contact)
+}
+
+// writeContactFile marshals contact to path.
+func writeContactFile(path string, contact Contact) error {
+	data, err := yaml.Marshal(&contact)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contact: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write contact file: %w", err)
+	}
+
+	return nil
+}
+
+func (yamlStore) Load(id string) (*Contact, error) {
+	data, err := os.ReadFile(getContactFilePath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("contact not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to read contact file: %w", err)
+	}
+
+	var contact Contact
+	if err := yaml.Unmarshal(data, &contact); err != nil {
+		return nil, fmt.Errorf("failed to parse contact file: %w", err)
+	}
+	contact.ID = id
+
+	return &contact, nil
+}
+
+func (yamlStore) Delete(id string) error {
+	if err := os.Remove(getContactFilePath(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("contact not found: %s", id)
+		}
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+	return nil
+}
+
+// List reads every contact file, migrating legacy files to "<id>.yml" as it goes.
+// Files that can't be read or parsed are skipped.
+func (yamlStore) List() ([]Contact, error) {
+	dir := GetContactsDir()
+
+	if err := ensureContactsDir(); err != nil {
+		return nil, fmt.Errorf("failed to create contacts directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contacts directory: %w", err)
+	}
+
+	var contacts []Contact
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yml") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var contact Contact
+		if err := yaml.Unmarshal(data, &contact); err != nil {
+			continue
+		}
+
+		contact, err = migrateContactFile(dir, entry.Name(), contact)
+		if err != nil {
+			continue
+		}
+
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, nil
+}
+
+func (yamlStore) Close() error {
+	return nil
+}
+
+// migrateContactFile moves a contact stored under a legacy "<Name>.yml" file, or one missing
+// an ID, to "<id>.yml". Returns the contact with its ID set.
+func migrateContactFile(dir, filename string, contact Contact) (Contact, error) {
+	if contact.ID != "" && isValidContactID(contact.ID) && filename == contact.ID+".yml" {
+		return contact, nil
+	}
+
+	oldPath := filepath.Join(dir, filename)
+	if contact.ID == "" || !isValidContactID(contact.ID) {
+		// A "<uuid>.yml" file that lost its id: line keeps the ID its filename already gives it.
+		if stem := strings.TrimSuffix(filename, ".yml"); isValidContactID(stem) {
+			contact.ID = stem
+		} else {
+			contact.ID = NewContactID()
+		}
+	}
+
+	newPath := getContactFilePath(contact.ID)
+	if newPath == oldPath {
+		return contact, writeContactFile(newPath, contact)
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		contact.ID = NewContactID()
+		newPath = getContactFilePath(contact.ID)
+	}
+
+	if err := writeContactFile(newPath, contact); err != nil {
+		return contact, err
+	}
+	if err := os.Remove(oldPath); err != nil {
+		return contact, fmt.Errorf("failed to remove migrated contact file: %w", err)
+	}
+
+	return contact, nil
+}
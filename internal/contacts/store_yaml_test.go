@@ -0,0 +1,77 @@
+package contacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/saravenpi/chime/internal/config"
+)
+
+func TestMigrateContactFile(t *testing.T) {
+	previous := config.Current()
+	t.Cleanup(func() { config.Set(previous) })
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	config.Set(cfg)
+
+	dir := GetContactsDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	const keptID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	files := map[string]string{
+		keptID + ".yml":  "name: Alice Martin\nphone_numbers: [\"+447700900123\"]\n",
+		"Bob Smith.yml":  "name: Bob Smith\n",
+		"not-a-uuid.yml": "id: also-not-a-uuid\nname: Carol Jones\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listed, err := yamlStore{}.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 3 {
+		t.Fatalf("List() returned %d contacts, want 3", len(listed))
+	}
+
+	for _, contact := range listed {
+		if !isValidContactID(contact.ID) {
+			t.Errorf("%s: ID %q is not a UUID", contact.Name, contact.ID)
+		}
+		if contact.Name == "Alice Martin" && contact.ID != keptID {
+			t.Errorf("Alice Martin: ID = %q, want the filename's %q", contact.ID, keptID)
+		}
+		if _, err := os.Stat(getContactFilePath(contact.ID)); err != nil {
+			t.Errorf("%s: %v", contact.Name, err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("contacts directory holds %d files after migration, want 3", len(entries))
+	}
+
+	// A second listing finds every file already migrated and leaves the IDs alone.
+	again, err := yamlStore{}.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string]bool)
+	for _, contact := range listed {
+		ids[contact.ID] = true
+	}
+	for _, contact := range again {
+		if !ids[contact.ID] {
+			t.Errorf("%s: ID changed to %q on the second listing", contact.Name, contact.ID)
+		}
+	}
+}
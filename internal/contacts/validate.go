@@ -0,0 +1,208 @@
+package contacts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InvalidIdentifier is a phone number or email SaveContact rejected, and why.
+type InvalidIdentifier struct {
+	// Identifier is the phone number or email as written in the contact.
+	Identifier string
+	Err        error
+}
+
+// ValidationError is returned by SaveContact when any of a contact's phone numbers or
+// emails is malformed or already belongs to another contact.
+type ValidationError struct {
+	Invalid []InvalidIdentifier
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Invalid))
+	for i, invalid := range e.Invalid {
+		messages[i] = invalid.Err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// For returns why identifier was rejected, or nil if it wasn't.
+func (e *ValidationError) For(identifier string) error {
+	identifier = strings.TrimSpace(identifier)
+	for _, invalid := range e.Invalid {
+		if invalid.Identifier == identifier {
+			return invalid.Err
+		}
+	}
+	return nil
+}
+
+// saveOptions relaxes the checks SaveContact makes, for callers that have done them already.
+type saveOptions struct {
+	// takeFrom lists contacts being merged into the saved one, whose phone numbers and
+	// emails it may share.
+	takeFrom []string
+	// ownersChecked is set when the caller already made sure no other contact has the
+	// contact's phone numbers or emails.
+	ownersChecked bool
+}
+
+// Canonical returns the normalised form of one of the contact's phone numbers or emails:
+// the form recorded when the contact was saved, or NormalizeIdentifier's if there is none.
+func (c Contact) Canonical(identifier string) string {
+	if normalized, ok := c.Normalized[identifier]; ok && normalized != "" {
+		return normalized
+	}
+	return NormalizeIdentifier(identifier)
+}
+
+// IdentifierOwners indexes every saved contact's phone numbers and emails by canonical form,
+// leaving out the contacts with the given IDs. Where contacts share an identifier, the first
+// listed keeps it.
+func IdentifierOwners(except ...string) (map[string]Contact, error) {
+	contacts, err := ListContacts()
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool, len(except))
+	for _, id := range except {
+		skip[id] = true
+	}
+	owners := make(map[string]Contact)
+	for _, contact := range contacts {
+		if skip[contact.ID] {
+			continue
+		}
+		for _, identifier := range append(append([]string{}, contact.PhoneNumbers...), contact.Emails...) {
+			if key := contact.Canonical(identifier); key != "" {
+				if _, ok := owners[key]; !ok {
+					owners[key] = contact
+				}
+			}
+		}
+	}
+	return owners, nil
+}
+
+// canonicalize trims the contact's phone numbers and emails, drops empty entries and repeats
+// of an earlier entry, and records each one's canonical form in Normalized. A canonical form
+// recorded by an earlier save is kept, so a national number stays in the region it was
+// entered in.
+func canonicalize(contact Contact) Contact {
+	normalized := make(map[string]string)
+	labels := make(map[string]string)
+	seen := make(map[string]bool)
+
+	clean := func(identifiers []string) []string {
+		var kept []string
+		for _, identifier := range identifiers {
+			trimmed := strings.TrimSpace(identifier)
+			if trimmed == "" {
+				continue
+			}
+			key := contact.Canonical(trimmed)
+			if key != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			kept = append(kept, trimmed)
+			if key != "" {
+				normalized[trimmed] = key
+			}
+			if label := contact.Labels[identifier]; label != "" {
+				labels[trimmed] = label
+			}
+		}
+		return kept
+	}
+
+	contact.PhoneNumbers = clean(contact.PhoneNumbers)
+	contact.Emails = clean(contact.Emails)
+	contact.Normalized = nil
+	if len(normalized) > 0 {
+		contact.Normalized = normalized
+	}
+	contact.Labels = nil
+	if len(labels) > 0 {
+		contact.Labels = labels
+	}
+	return contact
+}
+
+// validateIdentifiers checks the format of each of the contact's phone numbers and emails and,
+// unless opts.ownersChecked is set, that no other contact already has them. Identifiers the
+// contact had when it was last saved aren't checked again, so contacts saved by older
+// versions, or that already share a number, can still be edited and merged.
+func validateIdentifiers(contact Contact, opts saveOptions) error {
+	saved := make(map[string]bool)
+	if store, err := currentStore(); err == nil {
+		if existing, err := store.Load(contact.ID); err == nil {
+			for _, identifier := range append(append([]string{}, existing.PhoneNumbers...), existing.Emails...) {
+				saved[existing.Canonical(identifier)] = true
+			}
+		}
+	}
+
+	var invalid []InvalidIdentifier
+	var added []string
+	check := func(identifiers []string, validate func(string) error) {
+		for _, identifier := range identifiers {
+			if key := contact.Canonical(identifier); key != "" && saved[key] {
+				continue
+			}
+			if err := validate(identifier); err != nil {
+				invalid = append(invalid, InvalidIdentifier{Identifier: identifier, Err: err})
+				continue
+			}
+			added = append(added, identifier)
+		}
+	}
+	check(contact.PhoneNumbers, ValidatePhoneNumber)
+	check(contact.Emails, ValidateEmail)
+
+	if !opts.ownersChecked && len(added) > 0 {
+		owners, err := IdentifierOwners(append([]string{contact.ID}, opts.takeFrom...)...)
+		if err != nil {
+			return err
+		}
+
+		for _, identifier := range added {
+			if owner, ok := owners[contact.Canonical(identifier)]; ok {
+				invalid = append(invalid, InvalidIdentifier{
+					Identifier: identifier,
+					Err:        fmt.Errorf("%s already belongs to %s", identifier, owner.Name),
+				})
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return &ValidationError{Invalid: invalid}
+	}
+	return nil
+}
+
+// dropInvalidIdentifiers removes phone numbers and emails that fail validation, so imported
+// contacts can be saved. Returns the contact and a warning for each identifier removed.
+func dropInvalidIdentifiers(contact Contact) (Contact, []string) {
+	var warnings []string
+	keep := func(identifiers []string, validate func(string) error) []string {
+		var kept []string
+		for _, identifier := range identifiers {
+			if strings.TrimSpace(identifier) == "" {
+				continue
+			}
+			if err := validate(strings.TrimSpace(identifier)); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: skipped %v", contact.Name, err))
+				continue
+			}
+			kept = append(kept, identifier)
+		}
+		return kept
+	}
+
+	contact.PhoneNumbers = keep(contact.PhoneNumbers, ValidatePhoneNumber)
+	contact.Emails = keep(contact.Emails, ValidateEmail)
+	return contact, warnings
+}
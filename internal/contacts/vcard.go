@@ -0,0 +1,444 @@
+package contacts
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// vcardLineLimit is the maximum line length in octets before folding (RFC 6350 section 3.2).
+const vcardLineLimit = 75
+
+// ParseVCards reads every contact from a vCard 3.0 or 4.0 stream, which may hold one or
+// many BEGIN:VCARD...END:VCARD blocks. FN, N, TEL, EMAIL, NICKNAME, BDAY, ORG, NOTE and
+// CATEGORIES are imported; other properties are ignored. Cards without a name are skipped,
+// as are malformed lines; each skipped line is described in the returned warnings.
+func ParseVCards(r io.Reader) ([]Contact, []string, error) {
+	lines, err := unfoldVCardLines(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read vCard: %w", err)
+	}
+
+	var contacts []Contact
+	var warnings []string
+	var current *Contact
+	var structuredName string
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		name, params, value, ok := parseVCardProperty(line)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("line %d: skipped, not a vCard property: %q", i+1, line))
+			continue
+		}
+
+		switch name {
+		case "BEGIN":
+			if strings.EqualFold(value, "VCARD") {
+				current = &Contact{}
+				structuredName = ""
+			}
+			continue
+		case "END":
+			if strings.EqualFold(value, "VCARD") && current != nil {
+				if current.Name == "" {
+					current.Name = structuredName
+				}
+				if current.Name != "" {
+					contacts = append(contacts, *current)
+				}
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		switch name {
+		case "FN":
+			current.Name = unescapeVCardText(value)
+		case "N":
+			structuredName = parseStructuredName(value)
+		case "NICKNAME":
+			nicknames := splitVCardList(value, ',')
+			if len(nicknames) > 0 {
+				current.Nickname = nicknames[0]
+			}
+		case "BDAY":
+			current.Birthday = normalizeVCardDate(value)
+		case "ORG":
+			current.Organization = splitVCardList(value, ';')[0]
+		case "NOTE":
+			current.Notes = unescapeVCardText(value)
+		case "CATEGORIES":
+			for _, tag := range splitVCardList(value, ',') {
+				if tag != "" && !current.HasTag(tag) {
+					current.Tags = append(current.Tags, tag)
+				}
+			}
+		case "TEL":
+			phone := strings.TrimSpace(unescapeVCardText(value))
+			if strings.EqualFold(params["VALUE"], "uri") || strings.HasPrefix(strings.ToLower(phone), "tel:") {
+				phone = strings.TrimSpace(phone[strings.Index(phone, ":")+1:])
+			}
+			if phone != "" {
+				current.PhoneNumbers = append(current.PhoneNumbers, phone)
+				setLabel(current, phone, labelForVCardType(params["TYPE"]))
+			}
+		case "EMAIL":
+			email := strings.TrimSpace(unescapeVCardText(value))
+			email = strings.TrimPrefix(email, "mailto:")
+			if email != "" {
+				current.Emails = append(current.Emails, email)
+				setLabel(current, email, labelForVCardType(params["TYPE"]))
+			}
+		}
+	}
+
+	return contacts, warnings, nil
+}
+
+// WriteVCards writes contacts to w as vCard 3.0, one card per contact.
+func WriteVCards(w io.Writer, contacts []Contact) error {
+	bw := bufio.NewWriter(w)
+
+	for _, contact := range contacts {
+		writeVCardLine(bw, "BEGIN:VCARD")
+		writeVCardLine(bw, "VERSION:3.0")
+		writeVCardLine(bw, "FN:"+escapeVCardText(contact.Name))
+		writeVCardLine(bw, "N:"+formatStructuredName(contact.Name))
+		if contact.Nickname != "" {
+			writeVCardLine(bw, "NICKNAME:"+escapeVCardText(contact.Nickname))
+		}
+		if contact.Organization != "" {
+			writeVCardLine(bw, "ORG:"+escapeVCardText(contact.Organization))
+		}
+		if contact.Birthday != "" {
+			writeVCardLine(bw, "BDAY:"+contact.Birthday)
+		}
+		for _, phone := range contact.PhoneNumbers {
+			writeVCardLine(bw, "TEL;TYPE="+vcardTypeForLabel(contact.Label(phone), "CELL")+":"+escapeVCardText(phone))
+		}
+		for _, email := range contact.Emails {
+			emailType := "INTERNET"
+			if label := contact.Label(email); label != "" {
+				emailType += "," + vcardTypeForLabel(label, "")
+			}
+			writeVCardLine(bw, "EMAIL;TYPE="+emailType+":"+escapeVCardText(email))
+		}
+		if len(contact.Tags) > 0 {
+			escaped := make([]string, len(contact.Tags))
+			for i, tag := range contact.Tags {
+				escaped[i] = escapeVCardText(tag)
+			}
+			writeVCardLine(bw, "CATEGORIES:"+strings.Join(escaped, ","))
+		}
+		if contact.Notes != "" {
+			writeVCardLine(bw, "NOTE:"+escapeVCardText(contact.Notes))
+		}
+		writeVCardLine(bw, "END:VCARD")
+	}
+
+	return bw.Flush()
+}
+
+// ImportVCardFile imports every contact in the .vcf file at path using ImportContacts.
+// Returns the number of contacts imported and skipped, and the lines left out as malformed.
+func ImportVCardFile(path string) (int, int, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to open vCard file: %w", err)
+	}
+	defer f.Close()
+
+	parsed, warnings, err := ParseVCards(f)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if len(parsed) == 0 {
+		return 0, 0, warnings, fmt.Errorf("no contacts found in %s", path)
+	}
+
+	imported, skipped, err := ImportContacts(parsed)
+	return imported, skipped, warnings, err
+}
+
+// ExportVCardFile writes contacts to a .vcf file at path, replacing it if it exists.
+func ExportVCardFile(path string, contacts []Contact) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create vCard file: %w", err)
+	}
+
+	if err := WriteVCards(f, contacts); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write vCard file: %w", err)
+	}
+
+	return f.Close()
+}
+
+// labelForVCardType picks a contact label from a TYPE parameter such as "CELL,VOICE".
+// Types that only describe the medium (VOICE, INTERNET, PREF) are ignored, and custom
+// X- types such as "X-SCHOOL" become lowercase labels.
+func labelForVCardType(types string) string {
+	custom := ""
+	for _, t := range strings.Split(strings.ToUpper(types), ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "CELL", "IPHONE":
+			return "mobile"
+		case "HOME":
+			return "home"
+		case "WORK":
+			return "work"
+		case "OTHER":
+			return "other"
+		default:
+			if custom == "" && strings.HasPrefix(t, "X-") && len(t) > 2 {
+				custom = strings.ToLower(t[2:])
+			}
+		}
+	}
+	return custom
+}
+
+// vcardTypeForLabel is the inverse of labelForVCardType. Custom labels are written as X- types.
+func vcardTypeForLabel(label, fallback string) string {
+	switch strings.ToLower(label) {
+	case "":
+		return fallback
+	case "mobile":
+		return "CELL"
+	case "home", "work", "other":
+		return strings.ToUpper(label)
+	default:
+		var b strings.Builder
+		for _, r := range strings.ToUpper(label) {
+			if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+				b.WriteRune(r)
+			}
+		}
+		if b.Len() == 0 {
+			return fallback
+		}
+		return "X-" + b.String()
+	}
+}
+
+// setLabel records label for identifier on contact, leaving contact unchanged if label is empty.
+func setLabel(contact *Contact, identifier, label string) {
+	if label == "" {
+		return
+	}
+	if contact.Labels == nil {
+		contact.Labels = make(map[string]string)
+	}
+	contact.Labels[identifier] = label
+}
+
+// unfoldVCardLines splits r into logical lines, joining folded continuation lines
+// (lines starting with a space or tab) onto the previous line.
+func unfoldVCardLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+// parseVCardProperty splits "group.NAME;PARAM=x;TYPE=a,b:value" into its upper-cased name,
+// upper-cased parameters and raw value. Repeated TYPE parameters (TYPE=CELL;TYPE=VOICE, or
+// bare CELL;VOICE in vCard 2.1 style) are joined with commas.
+func parseVCardProperty(line string) (string, map[string]string, string, bool) {
+	colon := -1
+	inQuotes := false
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon == -1 {
+		return "", nil, "", false
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+
+	name := strings.ToUpper(parts[0])
+	if dot := strings.LastIndex(name, "."); dot != -1 {
+		name = name[dot+1:]
+	}
+
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		key, val, found := strings.Cut(param, "=")
+		if !found {
+			key, val = "TYPE", key
+		}
+		key, val = strings.ToUpper(key), strings.Trim(val, `"`)
+		if key == "TYPE" {
+			val = strings.ToUpper(val)
+			if params[key] != "" {
+				val = params[key] + "," + val
+			}
+		}
+		params[key] = val
+	}
+
+	return name, params, value, true
+}
+
+// parseStructuredName turns an N value (Family;Given;Additional;Prefix;Suffix) into a display name.
+func parseStructuredName(value string) string {
+	components := splitVCardList(value, ';')
+	for len(components) < 5 {
+		components = append(components, "")
+	}
+	family, given, additional, prefix, suffix := components[0], components[1], components[2], components[3], components[4]
+
+	var parts []string
+	for _, part := range []string{prefix, given, additional, family, suffix} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatStructuredName builds an N value from a display name, treating the last word as the family name.
+func formatStructuredName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return ";;;;"
+	}
+	if len(fields) == 1 {
+		return ";" + escapeVCardText(fields[0]) + ";;;"
+	}
+
+	family := fields[len(fields)-1]
+	given := strings.Join(fields[:len(fields)-1], " ")
+	return escapeVCardText(family) + ";" + escapeVCardText(given) + ";;;"
+}
+
+// normalizeVCardDate converts vCard dates (1990-01-15, 19900115, --0115) to YYYY-MM-DD or --MM-DD.
+func normalizeVCardDate(value string) string {
+	value = strings.TrimSpace(value)
+	if t := strings.Index(value, "T"); t != -1 {
+		value = value[:t]
+	}
+
+	switch {
+	case len(value) == 8 && isAllDigits(value):
+		return value[:4] + "-" + value[4:6] + "-" + value[6:]
+	case len(value) == 6 && strings.HasPrefix(value, "--") && isAllDigits(value[2:]):
+		return "--" + value[2:4] + "-" + value[4:]
+	default:
+		return value
+	}
+}
+
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// splitVCardList splits value on sep, honouring backslash escapes, and unescapes each item.
+func splitVCardList(value string, sep rune) []string {
+	var items []string
+	var current strings.Builder
+	escaped := false
+
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == sep:
+			items = append(items, strings.TrimSpace(unescapeVCardText(current.String())))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	items = append(items, strings.TrimSpace(unescapeVCardText(current.String())))
+
+	return items
+}
+
+func unescapeVCardText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func escapeVCardText(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, ";", "\\;")
+	s = strings.ReplaceAll(s, ",", "\\,")
+	s = strings.ReplaceAll(s, "\r\n", "\\n")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	return s
+}
+
+// writeVCardLine writes line with CRLF, folding it at vcardLineLimit octets without splitting UTF-8 sequences.
+func writeVCardLine(w *bufio.Writer, line string) {
+	limit := vcardLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = vcardLineLimit - 1
+	}
+	w.WriteString(line + "\r\n")
+}
+
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,250 @@
+package contacts
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const vcard30 = "BEGIN:VCARD\r\n" +
+	"VERSION:3.0\r\n" +
+	"FN:Alice Martin\r\n" +
+	"N:Martin;Alice;;;\r\n" +
+	"NICKNAME:Ali,Al\r\n" +
+	"BDAY:19900115\r\n" +
+	"item1.TEL;type=CELL;type=VOICE;type=pref:+44 7700 900123\r\n" +
+	"TEL;TYPE=HOME:020 7946 0018\r\n" +
+	"EMAIL;TYPE=INTERNET,WORK:alice@example.com\r\n" +
+	"EMAIL;TYPE=INTERNET:ali@example.org\r\n" +
+	"END:VCARD\r\n"
+
+const vcard40 = "BEGIN:VCARD\n" +
+	"VERSION:4.0\n" +
+	"N:Dupont;Jean;Pierre;Dr.;\n" +
+	"TEL;VALUE=uri;TYPE=\"work,voice\":tel:+33-1-42-68-53-00\n" +
+	"EMAIL;TYPE=home:mailto:jean@example.fr\n" +
+	"BDAY:--0412\n" +
+	"END:VCARD\n"
+
+const vcardMulti = "BEGIN:VCARD\r\n" +
+	"VERSION:3.0\r\n" +
+	"FN:Bob Smith\r\n" +
+	"TEL;CELL:(415) 555-0100\r\n" +
+	"END:VCARD\r\n" +
+	"BEGIN:VCARD\r\n" +
+	"VERSION:3.0\r\n" +
+	"N:;;;;\r\n" +
+	"TEL:+15555550199\r\n" +
+	"END:VCARD\r\n" +
+	"BEGIN:VCARD\r\n" +
+	"VERSION:4.0\r\n" +
+	"FN:Carol\r\n" +
+	"  Jones\r\n" +
+	"EMAIL;TYPE=OTHER:carol@example.com\r\n" +
+	"END:VCARD\r\n"
+
+func TestParseVCards(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Contact
+	}{
+		{
+			name:  "3.0 single",
+			input: vcard30,
+			want: []Contact{{
+				Name:         "Alice Martin",
+				Nickname:     "Ali",
+				Birthday:     "1990-01-15",
+				PhoneNumbers: []string{"+44 7700 900123", "020 7946 0018"},
+				Emails:       []string{"alice@example.com", "ali@example.org"},
+				Labels: map[string]string{
+					"+44 7700 900123":   "mobile",
+					"020 7946 0018":     "home",
+					"alice@example.com": "work",
+				},
+			}},
+		},
+		{
+			name:  "4.0 single",
+			input: vcard40,
+			want: []Contact{{
+				Name:         "Dr. Jean Pierre Dupont",
+				Birthday:     "--04-12",
+				PhoneNumbers: []string{"+33-1-42-68-53-00"},
+				Emails:       []string{"jean@example.fr"},
+				Labels: map[string]string{
+					"+33-1-42-68-53-00": "work",
+					"jean@example.fr":   "home",
+				},
+			}},
+		},
+		{
+			name:  "multiple, skipping a card without a name",
+			input: vcardMulti,
+			want: []Contact{
+				{
+					Name:         "Bob Smith",
+					PhoneNumbers: []string{"(415) 555-0100"},
+					Labels:       map[string]string{"(415) 555-0100": "mobile"},
+				},
+				{
+					Name:   "Carol Jones",
+					Emails: []string{"carol@example.com"},
+					Labels: map[string]string{"carol@example.com": "other"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := ParseVCards(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(warnings) != 0 {
+				t.Errorf("ParseVCards() warnings = %q, want none", warnings)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseVCards() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVCardsSkipsMalformedLines(t *testing.T) {
+	input := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"FN:Alice Martin\r\n" +
+		"this line has no colon\r\n" +
+		"TEL;TYPE=CELL:+447700900123\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"FN:Bob Smith\r\n" +
+		"END:VCARD\r\n"
+
+	got, warnings, err := ParseVCards(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "Alice Martin" || got[1].Name != "Bob Smith" {
+		t.Errorf("ParseVCards() = %#v, want Alice Martin and Bob Smith", got)
+	}
+	if !reflect.DeepEqual(got[0].PhoneNumbers, []string{"+447700900123"}) {
+		t.Errorf("PhoneNumbers = %q, want [+447700900123]", got[0].PhoneNumbers)
+	}
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "line 4:") {
+		t.Errorf("warnings = %q, want one warning for line 4", warnings)
+	}
+}
+
+func TestWriteVCardsRoundTrip(t *testing.T) {
+	for _, input := range []string{vcard30, vcard40, vcardMulti} {
+		parsed, _, err := ParseVCards(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range parsed {
+			parsed[i].Tags = []string{"family", "a, b"}
+			parsed[i].Notes = "line one\nline two; with a semicolon"
+			parsed[i].Organization = "Acme; Inc"
+			if len(parsed[i].Emails) > 0 {
+				parsed[i].Labels[parsed[i].Emails[0]] = "school"
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := WriteVCards(&buf, parsed); err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+			if len(line) > vcardLineLimit {
+				t.Errorf("line longer than %d octets: %q", vcardLineLimit, line)
+			}
+		}
+
+		got, warnings, err := ParseVCards(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("ParseVCards() warnings = %q, want none", warnings)
+		}
+		if !reflect.DeepEqual(got, parsed) {
+			t.Errorf("round trip =\n%#v\nwant\n%#v", got, parsed)
+		}
+	}
+}
+
+func TestWriteVCardsFoldsLongLines(t *testing.T) {
+	contact := Contact{Name: "Zoë", Notes: strings.Repeat("é", 100)}
+
+	var buf bytes.Buffer
+	if err := WriteVCards(&buf, []Contact{contact}); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := ParseVCards(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Notes != contact.Notes {
+		t.Errorf("ParseVCards() = %#v, want notes %q", got, contact.Notes)
+	}
+}
+
+func TestVCardTypeLabels(t *testing.T) {
+	tests := []struct {
+		types string
+		label string
+	}{
+		{"CELL,VOICE,PREF", "mobile"},
+		{"voice,iphone", "mobile"},
+		{"INTERNET,WORK", "work"},
+		{"HOME", "home"},
+		{"OTHER", "other"},
+		{"X-SCHOOL", "school"},
+		{"VOICE,X-HOLIDAY-HOME", "holiday-home"},
+		{"X-SCHOOL,WORK", "work"},
+		{"X-", ""},
+		{"INTERNET", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := labelForVCardType(tt.types); got != tt.label {
+			t.Errorf("labelForVCardType(%q) = %q, want %q", tt.types, got, tt.label)
+		}
+	}
+
+	for _, label := range []string{"mobile", "home", "work", "other", "school", "holiday-home", "pager2"} {
+		if got := labelForVCardType(vcardTypeForLabel(label, "")); got != label {
+			t.Errorf("label %q exported as %q reads back as %q", label, vcardTypeForLabel(label, ""), got)
+		}
+	}
+}
+
+func TestParseVCardProperty(t *testing.T) {
+	tests := []struct {
+		line   string
+		name   string
+		params map[string]string
+		value  string
+		ok     bool
+	}{
+		{"FN:Alice", "FN", map[string]string{}, "Alice", true},
+		{"item1.TEL;type=CELL;type=VOICE;type=pref:+1", "TEL", map[string]string{"TYPE": "CELL,VOICE,PREF"}, "+1", true},
+		{"TEL;CELL;HOME:+1", "TEL", map[string]string{"TYPE": "CELL,HOME"}, "+1", true},
+		{`EMAIL;TYPE="internet,work";PREF=1:a@b.c`, "EMAIL", map[string]string{"TYPE": "INTERNET,WORK", "PREF": "1"}, "a@b.c", true},
+		{`X-ABLABEL;X-NOTE="a:b":value`, "X-ABLABEL", map[string]string{"X-NOTE": "a:b"}, "value", true},
+		{"no colon here", "", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		name, params, value, ok := parseVCardProperty(tt.line)
+		if name != tt.name || !reflect.DeepEqual(params, tt.params) || value != tt.value || ok != tt.ok {
+			t.Errorf("parseVCardProperty(%q) = %q, %v, %q, %v, want %q, %v, %q, %v",
+				tt.line, name, params, value, ok, tt.name, tt.params, tt.value, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package contacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce groups the bursts of events editors and sync tools produce for a single save.
+const watchDebounce = 200 * time.Millisecond
+
+// WatchContacts watches the contacts directory for contact files being created, edited,
+// renamed or removed by other programs, or the contacts database when the SQLite backend is
+// active. Each burst of changes invalidates the contact cache and then calls onChange once,
+// from the watcher's goroutine.
+// The returned function stops the watcher.
+func WatchContacts(onChange func()) (func() error, error) {
+	dir, matches := GetContactsDir(), isContactFile
+	if Backend() == BackendSQLite {
+		dir, matches = filepath.Dir(GetSQLitePath()), isContactsDatabase
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create contacts directory: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contacts watcher: %w", err)
+	}
+
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch contacts directory: %w", err)
+	}
+
+	var mu sync.Mutex
+	var timer *time.Timer
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !matches(event.Name) || event.Op == fsnotify.Chmod {
+					continue
+				}
+
+				mu.Lock()
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(watchDebounce, func() {
+					InvalidateCache()
+					onChange()
+				})
+				mu.Unlock()
+
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	stop := func() error {
+		mu.Lock()
+		if timer != nil {
+			timer.Stop()
+		}
+		mu.Unlock()
+		return watcher.Close()
+	}
+
+	return stop, nil
+}
+
+// isContactFile reports whether path is a file ListContacts would read, ignoring editor swap files.
+func isContactFile(path string) bool {
+	name := filepath.Base(path)
+	return !strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".yml")
+}
+
+// isContactsDatabase reports whether path is the SQLite contacts database or its journal.
+func isContactsDatabase(path string) bool {
+	name := filepath.Base(path)
+	db := filepath.Base(GetSQLitePath())
+	return name == db || name == db+"-journal" || name == db+"-wal"
+}
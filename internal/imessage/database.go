@@ -2,10 +2,10 @@ package imessage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -13,6 +13,7 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/saravenpi/chime/internal/contacts"
 	"github.com/saravenpi/chime/internal/models"
+	"github.com/saravenpi/chime/internal/resolver"
 )
 
 func GetDBPath() string {
@@ -20,24 +21,9 @@ func GetDBPath() string {
 	return filepath.Join(homeDir, "Library", "Messages", "chat.db")
 }
 
+// GetContactsDBPath returns the path to the macOS AddressBook database.
 func GetContactsDBPath() string {
-	homeDir, _ := os.UserHomeDir()
-	baseDir := filepath.Join(homeDir, "Library", "Application Support", "AddressBook")
-
-	possibleVersions := []string{
-		"AddressBook-v22.abcddb",
-		"AddressBook-v23.abcddb",
-		"AddressBook-v24.abcddb",
-	}
-
-	for _, version := range possibleVersions {
-		path := filepath.Join(baseDir, version)
-		if _, err := os.Stat(path); err == nil {
-			return path
-		}
-	}
-
-	return filepath.Join(baseDir, "AddressBook-v22.abcddb")
+	return contacts.GetAddressBookDBPath()
 }
 
 func OpenDatabase() (*sql.DB, error) {
@@ -58,26 +44,15 @@ func OpenDatabaseReadWrite() (*sql.DB, error) {
 	return db, nil
 }
 
+// normalizePhoneNumber converts a phone number to E.164 using the default contacts region.
 func normalizePhoneNumber(phone string) string {
-	reg := regexp.MustCompile(`[^0-9+]`)
-	normalized := reg.ReplaceAllString(phone, "")
-	if strings.HasPrefix(normalized, "1") && len(normalized) == 11 {
-		normalized = normalized[1:]
-	}
-	if strings.HasPrefix(normalized, "+1") && len(normalized) == 12 {
-		normalized = normalized[2:]
-	}
-	return normalized
+	return contacts.NormalizePhoneNumber(phone, contacts.DefaultRegion())
 }
 
-// GetContactName retrieves a contact name from ~/.chime/contacts/.
-// Returns empty string if not found.
+// GetContactName returns the name for a handle using the resolver's source priority
+// (local contacts, then the macOS AddressBook by default). Returns empty string if not found.
 func GetContactName(identifier string) string {
-	if identifier == "" {
-		return ""
-	}
-
-	return contacts.FindContactByIdentifier(identifier)
+	return resolver.Lookup(identifier)
 }
 
 func extractTextFromAttributedBody(data []byte) string {
@@ -169,7 +144,37 @@ func indexOf(data []byte, pattern []byte) int {
 	return -1
 }
 
+// BlocklistError is returned by GetChats, together with the chats, when the blocklist or the
+// muted chats can't be read. The chats are then returned unfiltered or unmuted.
+type BlocklistError struct {
+	Errs []error
+}
+
+func (e *BlocklistError) Error() string {
+	messages := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *BlocklistError) Unwrap() []error {
+	return e.Errs
+}
+
+// GetChats returns every chat except blocked ones, most recent first. Muted chats are
+// marked and never report unread messages. If the blocklist or muted chats can't be read,
+// the chats are returned with a *BlocklistError.
 func GetChats() ([]models.Chat, error) {
+	return getChats(false)
+}
+
+// GetChatsIncludingBlocked returns every chat, with blocked chats marked, most recent first.
+func GetChatsIncludingBlocked() ([]models.Chat, error) {
+	return getChats(true)
+}
+
+func getChats(includeBlocked bool) ([]models.Chat, error) {
 	db, err := OpenDatabase()
 	if err != nil {
 		return nil, err
@@ -253,12 +258,7 @@ func GetChats() ([]models.Chat, error) {
 				if chat.DisplayName == "" && len(participants) > 0 {
 					participantNames := make([]string, 0, len(participants))
 					for _, p := range participants {
-						name := GetContactName(p)
-						if name != "" {
-							participantNames = append(participantNames, name)
-						} else {
-							participantNames = append(participantNames, p)
-						}
+						participantNames = append(participantNames, resolver.DisplayName(p))
 					}
 					chat.DisplayName = strings.Join(participantNames, ", ")
 				}
@@ -268,16 +268,98 @@ func GetChats() ([]models.Chat, error) {
 		}
 
 		if chat.DisplayName == "" {
-			contactName := GetContactName(chat.ChatID)
-			if contactName != "" {
-				chat.DisplayName = contactName
-			} else {
-				chat.DisplayName = chat.ChatID
+			chat.DisplayName = resolver.DisplayName(chat.ChatID)
+		}
+	}
+
+	return applyBlocklist(chats, includeBlocked)
+}
+
+// applyBlocklist marks muted and blocked chats, clearing HasUnread on muted ones, and drops
+// blocked chats unless includeBlocked is set. A blocklist or muted chats file that can't be
+// read is skipped and reported as a *BlocklistError.
+func applyBlocklist(chats []models.Chat, includeBlocked bool) ([]models.Chat, error) {
+	var errs []error
+	blocklist, err := contacts.LoadBlocklist()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	muted, err := contacts.ListMutedChats()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	visible := chats[:0]
+	for _, chat := range chats {
+		chat.Blocked = blocklist.BlocksChat(chat.ChatID, chat.Participants)
+		if chat.Blocked && !includeBlocked {
+			continue
+		}
+		if muted[chat.ChatID] {
+			chat.Muted = true
+			chat.HasUnread = false
+		}
+		visible = append(visible, chat)
+	}
+
+	if len(errs) > 0 {
+		return visible, &BlocklistError{Errs: errs}
+	}
+	return visible, nil
+}
+
+// chatsForLookup returns GetChats' chats for callers looking chats up, which go ahead
+// without the blocklist rather than fail when it can't be read.
+func chatsForLookup() ([]models.Chat, error) {
+	chats, err := GetChats()
+	var blocklistErr *BlocklistError
+	if errors.As(err, &blocklistErr) {
+		return chats, nil
+	}
+	return chats, err
+}
+
+// FindGroupChat returns the most recently active group chat whose participants are exactly
+// the given handles. Phone numbers are compared after normalisation.
+// Returns nil if no such chat exists yet.
+func FindGroupChat(participants []string) (*models.Chat, error) {
+	chats, err := chatsForLookup()
+	if err != nil {
+		return nil, err
+	}
+
+	want := make(map[string]bool, len(participants))
+	for _, p := range participants {
+		want[normalizeHandle(p)] = true
+	}
+
+	for _, chat := range chats {
+		if !chat.IsGroup || len(chat.Participants) != len(want) {
+			continue
+		}
+
+		matches := true
+		for _, p := range chat.Participants {
+			if !want[normalizeHandle(p)] {
+				matches = false
+				break
 			}
 		}
+		if matches {
+			found := chat
+			return &found, nil
+		}
 	}
 
-	return chats, nil
+	return nil, nil
+}
+
+// normalizeHandle normalises a phone number or lowercases an email for comparison.
+func normalizeHandle(handle string) string {
+	if strings.Contains(handle, "@") {
+		return strings.ToLower(strings.TrimSpace(handle))
+	}
+	return normalizePhoneNumber(handle)
 }
 
 // GetAllChatParticipants fetches participants for multiple chats in a single query.
@@ -387,10 +469,7 @@ func GetMessages(chatID int64) ([]models.Message, error) {
 		}
 
 		if !msg.IsFromMe && msg.Handle != "" {
-			contactName := GetContactName(msg.Handle)
-			if contactName != "" {
-				msg.Handle = contactName
-			}
+			msg.Handle = resolver.DisplayName(msg.Handle)
 		}
 
 		if dateNano > 0 {
@@ -0,0 +1,108 @@
+package imessage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/saravenpi/chime/internal/models"
+)
+
+// GetHandleActivity returns every handle in chat.db with the number of messages exchanged
+// with it and when the first and latest were sent, ordered by message count and then last activity.
+// A handle used over both iMessage and SMS is counted once.
+func GetHandleActivity() ([]models.HandleActivity, error) {
+	db, err := OpenDatabase()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	query := `
+		SELECT
+			h.id,
+			COUNT(m.ROWID) AS message_count,
+			COALESCE(MIN(m.date), 0) AS first_date,
+			COALESCE(MAX(m.date), 0) AS last_date
+		FROM handle h
+		JOIN message m ON m.handle_id = h.ROWID
+		WHERE h.id IS NOT NULL AND h.id != ''
+		GROUP BY h.id
+		ORDER BY message_count DESC, last_date DESC
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query handles: %w", err)
+	}
+	defer rows.Close()
+
+	var activity []models.HandleActivity
+	for rows.Next() {
+		var a models.HandleActivity
+		var firstNano, lastNano int64
+		if err := rows.Scan(&a.Handle, &a.MessageCount, &firstNano, &lastNano); err != nil {
+			continue
+		}
+		if firstNano > 0 {
+			a.FirstTime = time.Unix(0, firstNano+978307200000000000)
+		}
+		if lastNano > 0 {
+			a.LastTime = time.Unix(0, lastNano+978307200000000000)
+		}
+		activity = append(activity, a)
+	}
+
+	return activity, rows.Err()
+}
+
+// GetRecentMessagesForHandle returns up to limit of the latest messages sent to or received
+// from handle in any chat, oldest first. Handle names are left unresolved.
+func GetRecentMessagesForHandle(handle string, limit int) ([]models.Message, error) {
+	db, err := OpenDatabase()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	query := `
+		SELECT
+			m.ROWID,
+			m.guid,
+			COALESCE(m.text, ''),
+			m.attributedBody,
+			m.is_from_me,
+			m.date
+		FROM message m
+		JOIN handle h ON m.handle_id = h.ROWID
+		WHERE h.id = ?
+		ORDER BY m.date DESC
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, handle, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages for %s: %w", handle, err)
+	}
+	defer rows.Close()
+
+	var messages []models.Message
+	for rows.Next() {
+		msg := models.Message{Handle: handle}
+		var dateNano int64
+		var attributedBody []byte
+		if err := rows.Scan(&msg.ROWID, &msg.GUID, &msg.Text, &attributedBody, &msg.IsFromMe, &dateNano); err != nil {
+			continue
+		}
+
+		if msg.Text == "" && len(attributedBody) > 0 {
+			msg.Text = extractTextFromAttributedBody(attributedBody)
+		}
+		if dateNano > 0 {
+			msg.Date = time.Unix(0, dateNano+978307200000000000)
+		}
+
+		messages = append([]models.Message{msg}, messages...)
+	}
+
+	return messages, rows.Err()
+}
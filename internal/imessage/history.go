@@ -0,0 +1,149 @@
+package imessage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/saravenpi/chime/internal/models"
+)
+
+// GetContactHistory collects a contact's activity across all of their handles: per-handle
+// message counts in the order the handles were given, and every chat (1:1 or group) one of
+// the handles takes part in, most recently active first. Handles are compared after
+// normalisation, so "+44 7700 900123" matches "+447700900123" in chat.db.
+func GetContactHistory(handles []string) (models.ContactHistory, error) {
+	var history models.ContactHistory
+
+	want := make(map[string]int, len(handles))
+	for i, handle := range handles {
+		if key := normalizeHandle(handle); key != "" {
+			if _, ok := want[key]; !ok {
+				want[key] = i
+			}
+		}
+	}
+
+	history.Handles = make([]models.HandleActivity, len(handles))
+	for i, handle := range handles {
+		history.Handles[i] = models.HandleActivity{Handle: handle}
+	}
+	if len(want) == 0 {
+		return history, nil
+	}
+
+	activity, err := GetHandleActivity()
+	if err != nil {
+		return history, err
+	}
+
+	var dbHandles []string
+	for _, a := range activity {
+		i, ok := want[normalizeHandle(a.Handle)]
+		if !ok {
+			continue
+		}
+		dbHandles = append(dbHandles, a.Handle)
+
+		h := &history.Handles[i]
+		h.MessageCount += a.MessageCount
+		if !a.FirstTime.IsZero() && (h.FirstTime.IsZero() || a.FirstTime.Before(h.FirstTime)) {
+			h.FirstTime = a.FirstTime
+		}
+		if a.LastTime.After(h.LastTime) {
+			h.LastTime = a.LastTime
+		}
+	}
+
+	chats, err := chatsForLookup()
+	if err != nil {
+		return history, err
+	}
+
+	byID := make(map[int64]models.Chat)
+	var chatIDs []int64
+	for _, chat := range chats {
+		for _, p := range chat.Participants {
+			if _, ok := want[normalizeHandle(p)]; ok {
+				byID[chat.ROWID] = chat
+				chatIDs = append(chatIDs, chat.ROWID)
+				break
+			}
+		}
+	}
+	if len(chatIDs) == 0 {
+		return history, nil
+	}
+
+	db, err := OpenDatabase()
+	if err != nil {
+		return history, err
+	}
+	defer db.Close()
+
+	handleMarks := "''"
+	if len(dbHandles) > 0 {
+		handleMarks = strings.TrimSuffix(strings.Repeat("?,", len(dbHandles)), ",")
+	}
+	chatMarks := strings.TrimSuffix(strings.Repeat("?,", len(chatIDs)), ",")
+
+	query := fmt.Sprintf(`
+		SELECT
+			cmj.chat_id,
+			COUNT(m.ROWID),
+			COALESCE(SUM(CASE WHEN m.is_from_me = 0 AND h.id IN (%s) THEN 1 ELSE 0 END), 0),
+			COALESCE(MIN(m.date), 0),
+			COALESCE(MAX(m.date), 0)
+		FROM chat_message_join cmj
+		JOIN message m ON cmj.message_id = m.ROWID
+		LEFT JOIN handle h ON m.handle_id = h.ROWID
+		WHERE cmj.chat_id IN (%s)
+		GROUP BY cmj.chat_id
+	`, handleMarks, chatMarks)
+
+	args := make([]interface{}, 0, len(dbHandles)+len(chatIDs))
+	for _, handle := range dbHandles {
+		args = append(args, handle)
+	}
+	for _, id := range chatIDs {
+		args = append(args, id)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return history, fmt.Errorf("failed to query chat history: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[int64]models.ChatActivity)
+	for rows.Next() {
+		var chatID int64
+		var a models.ChatActivity
+		var firstNano, lastNano int64
+		if err := rows.Scan(&chatID, &a.MessageCount, &a.FromContact, &firstNano, &lastNano); err != nil {
+			continue
+		}
+		if firstNano > 0 {
+			a.FirstTime = time.Unix(0, firstNano+978307200000000000)
+		}
+		if lastNano > 0 {
+			a.LastTime = time.Unix(0, lastNano+978307200000000000)
+		}
+		stats[chatID] = a
+	}
+	if err := rows.Err(); err != nil {
+		return history, fmt.Errorf("failed to read chat history: %w", err)
+	}
+
+	for _, id := range chatIDs {
+		a := stats[id]
+		a.Chat = byID[id]
+		history.Chats = append(history.Chats, a)
+	}
+	sort.SliceStable(history.Chats, func(i, j int) bool {
+		return history.Chats[i].LastTime.After(history.Chats[j].LastTime)
+	})
+
+	return history, nil
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
-	"sync"
 
 	"github.com/saravenpi/chime/internal/models"
 )
@@ -20,119 +19,6 @@ func escapeAppleScript(s string) string {
 	return s
 }
 
-var contactCache = make(map[string]string)
-var contactCacheMutex sync.RWMutex
-var contactCacheInitialized bool
-
-// LoadContactForIdentifier asynchronously looks up a contact name for the given identifier
-// (phone number or email). Results are cached and delivered via callback when found.
-func LoadContactForIdentifier(identifier string, callback func(string, string)) {
-	go func() {
-		contactCacheMutex.RLock()
-		if name, ok := contactCache[identifier]; ok {
-			contactCacheMutex.RUnlock()
-			callback(identifier, name)
-			return
-		}
-		contactCacheMutex.RUnlock()
-
-		cleanedIdentifier := strings.Map(func(r rune) rune {
-			if (r >= '0' && r <= '9') || r == '+' {
-				return r
-			}
-			return -1
-		}, identifier)
-
-		script := fmt.Sprintf(`
-			tell application "Contacts"
-				set targetNumber to "%s"
-				try
-					repeat with aPerson in people
-						try
-							repeat with aPhone in phones of aPerson
-								set phoneValue to value of aPhone
-								set cleanPhone to do shell script "echo " & quoted form of phoneValue & " | tr -cd '0-9+'"
-								if cleanPhone contains targetNumber or targetNumber contains cleanPhone then
-									return name of aPerson
-								end if
-							end repeat
-						end try
-						try
-							repeat with anEmail in emails of aPerson
-								set emailValue to value of anEmail
-								if emailValue is equal to "%s" then
-									return name of aPerson
-								end if
-							end repeat
-						end try
-					end repeat
-				end try
-				return ""
-			end tell
-		`, escapeAppleScript(cleanedIdentifier), escapeAppleScript(identifier))
-
-		cmd := exec.Command("osascript", "-e", script)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return
-		}
-
-		name := strings.TrimSpace(string(output))
-		if name != "" && name != "missing value" {
-			contactCacheMutex.Lock()
-			contactCache[identifier] = name
-			contactCache[cleanedIdentifier] = name
-			contactCacheMutex.Unlock()
-			callback(identifier, name)
-		}
-	}()
-}
-
-// GetContactNameViaAppleScript retrieves a contact name from the cache.
-// Returns empty string if not found. Does not trigger a lookup.
-func GetContactNameViaAppleScript(identifier string) string {
-	if identifier == "" {
-		return ""
-	}
-
-	contactCacheMutex.RLock()
-	defer contactCacheMutex.RUnlock()
-
-	if name, ok := contactCache[identifier]; ok {
-		return name
-	}
-
-	cleanedIdentifier := strings.Map(func(r rune) rune {
-		if (r >= '0' && r <= '9') || r == '+' {
-			return r
-		}
-		return -1
-	}, identifier)
-
-	if cleanedIdentifier != identifier {
-		if name, ok := contactCache[cleanedIdentifier]; ok {
-			return name
-		}
-	}
-
-	for cachedID, name := range contactCache {
-		cachedCleaned := strings.Map(func(r rune) rune {
-			if (r >= '0' && r <= '9') || r == '+' {
-				return r
-			}
-			return -1
-		}, cachedID)
-
-		if strings.Contains(cachedCleaned, cleanedIdentifier) || strings.Contains(cleanedIdentifier, cachedCleaned) {
-			if len(cleanedIdentifier) >= 8 && len(cachedCleaned) >= 8 {
-				return name
-			}
-		}
-	}
-
-	return ""
-}
-
 // SendMessageToChat sends a message to the specified chat using AppleScript.
 // For group chats, tries multiple strategies: chat ID, display name, and participant list.
 func SendMessageToChat(chat models.Chat, message string) error {
@@ -176,6 +62,14 @@ func SendMessage(recipient, message string) error {
 	return sendIndividualMessage(recipient, message)
 }
 
+// StartGroupChat creates a new group chat with the given participants and sends the first message to it.
+func StartGroupChat(participants []string, message string) error {
+	if len(participants) < 2 {
+		return fmt.Errorf("a group chat needs at least two participants")
+	}
+	return sendGroupMessage(participants, message)
+}
+
 // sendIndividualMessage sends a message to an individual contact.
 // Tries iMessage first, then falls back to SMS if iMessage fails.
 func sendIndividualMessage(recipient, message string) error {
@@ -0,0 +1,135 @@
+package imessage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SendStatus describes what chat.db reports about a message after it was handed to Messages.app.
+type SendStatus int
+
+const (
+	SendStatusUnconfirmed SendStatus = iota
+	SendStatusSent
+	SendStatusFailed
+)
+
+func (s SendStatus) String() string {
+	switch s {
+	case SendStatusSent:
+		return "sent"
+	case SendStatusFailed:
+		return "failed"
+	default:
+		return "unconfirmed"
+	}
+}
+
+// DefaultVerifyTimeout is how long VerifySend watches chat.db before giving up.
+const DefaultVerifyTimeout = 15 * time.Second
+
+const verifyPollInterval = 500 * time.Millisecond
+
+// GetLatestMessageRowID returns the highest message ROWID currently in chat.db.
+// It is used as a baseline so VerifySend only considers rows written after a send.
+func GetLatestMessageRowID() (int64, error) {
+	db, err := OpenDatabase()
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var rowID sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(ROWID) FROM message`).Scan(&rowID); err != nil {
+		return 0, fmt.Errorf("failed to query latest message: %w", err)
+	}
+
+	return rowID.Int64, nil
+}
+
+// VerifySend watches chat.db for an outgoing message with the given text in the chat
+// identified by chatIdentifier (the chat_identifier column, e.g. "+15551234567" or "chat123...").
+// Only rows with a ROWID greater than afterRowID are considered.
+// Returns SendStatusSent once the row is marked as sent, SendStatusFailed if Messages.app
+// recorded an error on it, and SendStatusUnconfirmed if neither happens before the timeout.
+func VerifySend(chatIdentifier, text string, afterRowID int64, timeout time.Duration) (SendStatus, error) {
+	db, err := OpenDatabase()
+	if err != nil {
+		return SendStatusUnconfirmed, err
+	}
+	defer db.Close()
+
+	query := `
+		SELECT
+			m.ROWID,
+			COALESCE(m.text, ''),
+			m.attributedBody,
+			COALESCE(m.error, 0),
+			COALESCE(m.is_sent, 0)
+		FROM message m
+		JOIN chat_message_join cmj ON m.ROWID = cmj.message_id
+		JOIN chat c ON cmj.chat_id = c.ROWID
+		WHERE c.chat_identifier = ?
+		AND m.is_from_me = 1
+		AND m.ROWID > ?
+		ORDER BY m.ROWID ASC
+	`
+
+	want := strings.TrimSpace(text)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, found, err := checkOutgoingMessage(db, query, chatIdentifier, want, afterRowID)
+		if err != nil {
+			return SendStatusUnconfirmed, err
+		}
+		if found && status != SendStatusUnconfirmed {
+			return status, nil
+		}
+
+		if time.Now().After(deadline) {
+			return SendStatusUnconfirmed, nil
+		}
+		time.Sleep(verifyPollInterval)
+	}
+}
+
+// checkOutgoingMessage runs one poll of VerifySend.
+// found reports whether a matching row exists at all, even if it is not sent yet.
+func checkOutgoingMessage(db *sql.DB, query, chatIdentifier, want string, afterRowID int64) (SendStatus, bool, error) {
+	rows, err := db.Query(query, chatIdentifier, afterRowID)
+	if err != nil {
+		return SendStatusUnconfirmed, false, fmt.Errorf("failed to query outgoing messages: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rowID int64
+		var msgText string
+		var attributedBody []byte
+		var errorCode int
+		var isSent bool
+		if err := rows.Scan(&rowID, &msgText, &attributedBody, &errorCode, &isSent); err != nil {
+			continue
+		}
+
+		if msgText == "" && len(attributedBody) > 0 {
+			msgText = extractTextFromAttributedBody(attributedBody)
+		}
+		if strings.TrimSpace(msgText) != want {
+			continue
+		}
+
+		if errorCode != 0 {
+			return SendStatusFailed, true, nil
+		}
+		if isSent {
+			return SendStatusSent, true, nil
+		}
+		return SendStatusUnconfirmed, true, nil
+	}
+
+	return SendStatusUnconfirmed, false, rows.Err()
+}
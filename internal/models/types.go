@@ -12,6 +12,8 @@ type Chat struct {
 	Participants []string
 	IsGroup      bool
 	HasUnread    bool
+	Muted        bool
+	Blocked      bool
 }
 
 type Message struct {
@@ -31,3 +33,23 @@ const (
 	ViewList ViewMode = iota
 	ViewDetail
 )
+
+type HandleActivity struct {
+	Handle       string
+	MessageCount int
+	FirstTime    time.Time
+	LastTime     time.Time
+}
+
+type ChatActivity struct {
+	Chat         Chat
+	MessageCount int
+	FromContact  int
+	FirstTime    time.Time
+	LastTime     time.Time
+}
+
+type ContactHistory struct {
+	Handles []HandleActivity
+	Chats   []ChatActivity
+}
@@ -0,0 +1,155 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saravenpi/chime/internal/contacts"
+)
+
+// Source names accepted by SetOrder.
+const (
+	SourceLocal       = "local"
+	SourceAddressBook = "addressbook"
+)
+
+// DefaultOrder is the source priority used until SetOrder is called.
+var DefaultOrder = []string{SourceLocal, SourceAddressBook}
+
+var (
+	// PositiveTTL is how long a resolved name is cached.
+	PositiveTTL = 5 * time.Minute
+	// NegativeTTL is how long a handle that no source knows is cached as unknown.
+	NegativeTTL = time.Minute
+)
+
+// sources maps each source name to a lookup returning a display name, or "" if it has none.
+var sources = map[string]func(identifier string) string{
+	SourceLocal:       contacts.FindContactByIdentifier,
+	SourceAddressBook: contacts.FindAddressBookName,
+}
+
+// Result is the outcome of resolving a handle.
+type Result struct {
+	// Name is the resolved display name, or the handle itself when no source knows it.
+	Name string
+	// Source is the name of the source that matched, or "" for the handle fallback.
+	Source string
+}
+
+// Found reports whether a source matched, as opposed to falling back to the handle.
+func (r Result) Found() bool {
+	return r.Source != ""
+}
+
+type cacheEntry struct {
+	result  Result
+	expires time.Time
+}
+
+var (
+	mu         sync.RWMutex
+	order      = append([]string(nil), DefaultOrder...)
+	cache      = make(map[string]cacheEntry)
+	generation = contacts.Generation()
+)
+
+// SetOrder sets which sources are consulted and in what order, e.g. ["addressbook", "local"].
+// Sources left out are not consulted. The cache is cleared.
+func SetOrder(names []string) error {
+	var newOrder []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := sources[name]; !ok {
+			return fmt.Errorf("unknown name source %q (expected %s or %s)", name, SourceLocal, SourceAddressBook)
+		}
+		seen[name] = true
+		newOrder = append(newOrder, name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	order = newOrder
+	cache = make(map[string]cacheEntry)
+	return nil
+}
+
+// Order returns the current source priority.
+func Order() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]string(nil), order...)
+}
+
+// Resolve turns a handle (phone number or email) into a display name by trying each source in
+// priority order, falling back to the handle itself. Results, including misses, are cached until
+// their TTL expires or contacts.Generation changes.
+func Resolve(identifier string) Result {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return Result{}
+	}
+	key := contacts.NormalizeIdentifier(identifier)
+
+	mu.RLock()
+	entry, ok := cache[key]
+	stale := generation != contacts.Generation()
+	currentOrder := order
+	mu.RUnlock()
+
+	if ok && !stale && time.Now().Before(entry.expires) {
+		if !entry.result.Found() {
+			return Result{Name: identifier}
+		}
+		return entry.result
+	}
+
+	result := Result{Name: identifier}
+	for _, name := range currentOrder {
+		if found := sources[name](identifier); found != "" {
+			result = Result{Name: found, Source: name}
+			break
+		}
+	}
+
+	ttl := PositiveTTL
+	if !result.Found() {
+		ttl = NegativeTTL
+	}
+
+	mu.Lock()
+	if current := contacts.Generation(); generation != current {
+		cache = make(map[string]cacheEntry)
+		generation = current
+	}
+	cache[key] = cacheEntry{result: result, expires: time.Now().Add(ttl)}
+	mu.Unlock()
+
+	return result
+}
+
+// Lookup returns the name for identifier, or "" if no source knows it.
+func Lookup(identifier string) string {
+	if result := Resolve(identifier); result.Found() {
+		return result.Name
+	}
+	return ""
+}
+
+// DisplayName returns the name for identifier, or the identifier itself if no source knows it.
+func DisplayName(identifier string) string {
+	return Resolve(identifier).Name
+}
+
+// Invalidate clears the cache, so the next lookups go back to the sources.
+func Invalidate() {
+	mu.Lock()
+	defer mu.Unlock()
+	cache = make(map[string]cacheEntry)
+}
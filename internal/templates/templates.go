@@ -0,0 +1,116 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/saravenpi/chime/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a reusable message stored as a YAML file in ~/.chime/templates/.
+type Template struct {
+	Name string `yaml:"name"`
+	Body string `yaml:"body"`
+}
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// GetTemplatesDir returns the path to the templates directory (~/.chime/templates, or
+// "templates" in the configured data directory).
+func GetTemplatesDir() string {
+	return filepath.Join(config.DataDir(), "templates")
+}
+
+// ListTemplates returns all templates from the templates directory, sorted by name.
+// Files without a name field use their file name instead.
+func ListTemplates() ([]Template, error) {
+	dir := GetTemplatesDir()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var template Template
+		if err := yaml.Unmarshal(data, &template); err != nil {
+			continue
+		}
+
+		if template.Name == "" {
+			template.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		if strings.TrimSpace(template.Body) == "" {
+			continue
+		}
+
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return strings.ToLower(templates[i].Name) < strings.ToLower(templates[j].Name)
+	})
+
+	return templates, nil
+}
+
+// Vars builds the template variables for a recipient called name at time now:
+// name, first_name, last_name, date, time and weekday.
+func Vars(name string, now time.Time) map[string]string {
+	vars := map[string]string{
+		"name":       name,
+		"first_name": "",
+		"last_name":  "",
+		"date":       now.Format(config.Current().LongDateLayout()),
+		"time":       now.Format(config.Current().ClockLayout()),
+		"weekday":    now.Format("Monday"),
+	}
+
+	fields := strings.Fields(name)
+	if len(fields) > 0 {
+		vars["first_name"] = fields[0]
+	}
+	if len(fields) > 1 {
+		vars["last_name"] = fields[len(fields)-1]
+	}
+
+	return vars
+}
+
+// Expand replaces {{variable}} placeholders in body with values from vars.
+// Unknown variables are left untouched so typos stay visible before sending.
+func Expand(body string, vars map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(body, func(match string) string {
+		key := variablePattern.FindStringSubmatch(match)[1]
+		if value, ok := vars[key]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// HasVariables reports whether body contains any {{variable}} placeholder.
+func HasVariables(body string) bool {
+	return variablePattern.MatchString(body)
+}
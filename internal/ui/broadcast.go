@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/saravenpi/chime/internal/imessage"
+	"github.com/saravenpi/chime/internal/templates"
+)
+
+type broadcastStatus int
+
+const (
+	broadcastPending broadcastStatus = iota
+	broadcastSending
+	broadcastSent
+	broadcastFailed
+)
+
+type broadcastTarget struct {
+	recipient
+	status broadcastStatus
+	err    error
+}
+
+type broadcastResultMsg struct {
+	index int
+	err   error
+}
+
+type BroadcastModel struct {
+	recipients   []broadcastTarget
+	message      string
+	personalize  bool
+	current      int
+	done         bool
+	spinner      spinner.Model
+	windowWidth  int
+	windowHeight int
+	filters      conversationFilters
+}
+
+// NewBroadcastModel creates a view that sends message individually to every recipient.
+// When personalize is set, each copy is addressed to the recipient by name.
+func NewBroadcastModel(recipients []recipient, message string, personalize bool, filters conversationFilters) BroadcastModel {
+	targets := make([]broadcastTarget, len(recipients))
+	for i, r := range recipients {
+		targets[i] = broadcastTarget{recipient: r}
+	}
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = statusStyle
+
+	return BroadcastModel{
+		recipients:   targets,
+		message:      message,
+		personalize:  personalize,
+		spinner:      s,
+		windowWidth:  80,
+		windowHeight: 30,
+		filters:      filters,
+	}
+}
+
+func (m BroadcastModel) Init() tea.Cmd {
+	if len(m.recipients) == 0 {
+		return nil
+	}
+	m.recipients[0].status = broadcastSending
+	return tea.Batch(m.spinner.Tick, m.sendCmd(0))
+}
+
+func (m BroadcastModel) sendCmd(index int) tea.Cmd {
+	recipient := m.recipients[index]
+	message := m.message
+	if m.personalize {
+		message = personalizeMessage(message, recipient.name)
+	}
+
+	return func() tea.Msg {
+		err := imessage.SendMessage(recipient.handle, message)
+		return broadcastResultMsg{index: index, err: err}
+	}
+}
+
+func (m BroadcastModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		return m, nil
+
+	case broadcastResultMsg:
+		if msg.err != nil {
+			m.recipients[msg.index].status = broadcastFailed
+			m.recipients[msg.index].err = msg.err
+		} else {
+			m.recipients[msg.index].status = broadcastSent
+		}
+
+		m.current = msg.index + 1
+		if m.current >= len(m.recipients) {
+			m.done = true
+			return m, nil
+		}
+
+		m.recipients[m.current].status = broadcastSending
+		return m, m.sendCmd(m.current)
+
+	case spinner.TickMsg:
+		if !m.done {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.ForceQuit):
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Back, keys.Submit, keys.Quit):
+			if !m.done {
+				return m, nil
+			}
+			conversationsModel := newFilteredConversationsModel(m.filters)
+			if m.windowWidth > 0 {
+				updatedModel, _ := conversationsModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+				conversationsModel = updatedModel.(ConversationsModel)
+			}
+			return conversationsModel, conversationsModel.Init()
+		}
+	}
+
+	return m, nil
+}
+
+func (m BroadcastModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Broadcast to %d recipients", len(m.recipients))) + "\n\n")
+
+	sent, failed := 0, 0
+	for _, r := range m.recipients {
+		label := r.handle
+		if r.name != "" && r.name != r.handle {
+			label = fmt.Sprintf("%s (%s)", r.name, r.handle)
+		}
+
+		switch r.status {
+		case broadcastPending:
+			b.WriteString(helpStyle.Render("  • "+label) + "\n")
+		case broadcastSending:
+			b.WriteString(fmt.Sprintf("  %s %s\n", m.spinner.View(), normalStyle.Render(label)))
+		case broadcastSent:
+			sent++
+			b.WriteString(statusStyle.Render("  ✓ "+label) + "\n")
+		case broadcastFailed:
+			failed++
+			b.WriteString(errorStyle.Render(fmt.Sprintf("  ✗ %s: %v", label, r.err)) + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if m.done {
+		b.WriteString(normalStyle.Render(fmt.Sprintf("Done: %d sent, %d failed", sent, failed)) + "\n\n")
+		b.WriteString(helpView(m.windowWidth, relabel(keys.Back, "back to conversations")))
+	} else {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("Sending %d of %d...", m.current+1, len(m.recipients))))
+	}
+
+	return b.String()
+}
+
+// personalizeMessage addresses message to name by expanding template variables such as
+// {{first_name}}; if the message has none, a greeting is prepended instead.
+func personalizeMessage(message, name string) string {
+	vars := templates.Vars(name, time.Now())
+	if templates.HasVariables(message) || vars["first_name"] == "" {
+		return templates.Expand(message, vars)
+	}
+
+	return fmt.Sprintf("Hi %s, %s", vars["first_name"], message)
+}
@@ -0,0 +1,333 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/saravenpi/chime/internal/contacts"
+	"github.com/saravenpi/chime/internal/imessage"
+	"github.com/saravenpi/chime/internal/models"
+)
+
+type contactChatItem struct {
+	activity models.ChatActivity
+}
+
+func (i contactChatItem) FilterValue() string { return i.activity.Chat.DisplayName }
+func (i contactChatItem) Title() string {
+	if i.activity.Chat.IsGroup {
+		return "👥 " + i.activity.Chat.DisplayName
+	}
+	return i.activity.Chat.DisplayName
+}
+func (i contactChatItem) Description() string {
+	return fmt.Sprintf("%d messages (%d from them) • %s", i.activity.MessageCount, i.activity.FromContact, formatDateRange(i.activity.FirstTime, i.activity.LastTime))
+}
+
+type contactHistoryLoadedMsg struct {
+	history models.ContactHistory
+	err     error
+}
+
+type contactReloadedMsg struct {
+	contact *contacts.Contact
+	err     error
+}
+
+type ContactDetailModel struct {
+	contact      contacts.Contact
+	history      models.ContactHistory
+	list         list.Model
+	loading      bool
+	err          error
+	windowWidth  int
+	windowHeight int
+	showHelp     bool
+}
+
+// NewContactDetailModel creates a screen showing a contact's handles and message history.
+func NewContactDetailModel(contact contacts.Contact) ContactDetailModel {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(accentColor).
+		Bold(true)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(dimColor)
+
+	l := list.New([]list.Item{}, delegate, 80, 10)
+	l.Title = "Conversations"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.KeyMap = listKeyMap()
+
+	return ContactDetailModel{
+		contact:      contact,
+		list:         l,
+		loading:      true,
+		windowWidth:  80,
+		windowHeight: 30,
+	}
+}
+
+func (m ContactDetailModel) Init() tea.Cmd {
+	return m.loadHistoryCmd()
+}
+
+func (m ContactDetailModel) handles() []string {
+	return append(append([]string{}, m.contact.PhoneNumbers...), m.contact.Emails...)
+}
+
+func (m ContactDetailModel) loadHistoryCmd() tea.Cmd {
+	handles := m.handles()
+	return func() tea.Msg {
+		history, err := imessage.GetContactHistory(handles)
+		return contactHistoryLoadedMsg{history: history, err: err}
+	}
+}
+
+func (m ContactDetailModel) reloadContactCmd() tea.Cmd {
+	id := m.contact.ID
+	return func() tea.Msg {
+		contact, err := contacts.LoadContact(id)
+		return contactReloadedMsg{contact: contact, err: err}
+	}
+}
+
+func (m *ContactDetailModel) resizeList() {
+	height := m.windowHeight - lipgloss.Height(m.header()) - 2
+	if height < 4 {
+		height = 4
+	}
+	m.list.SetWidth(m.windowWidth)
+	m.list.SetHeight(height)
+}
+
+func (m ContactDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		m.resizeList()
+		return m, nil
+
+	case contactHistoryLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+
+		m.err = nil
+		m.history = msg.history
+		items := make([]list.Item, len(m.history.Chats))
+		for i, activity := range m.history.Chats {
+			items[i] = contactChatItem{activity: activity}
+		}
+		m.list.SetItems(items)
+		m.list.Title = fmt.Sprintf("Conversations - %d", len(items))
+		m.resizeList()
+		return m, nil
+
+	case contactReloadedMsg:
+		if msg.err != nil {
+			return m.backToContacts()
+		}
+		m.contact = *msg.contact
+		return m, m.loadHistoryCmd()
+
+	case ContactsChangedMsg:
+		return m, m.reloadContactCmd()
+
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.ForceQuit) {
+			return m, tea.Quit
+		}
+
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, keys.Back):
+			return m.backToContacts()
+
+		case key.Matches(msg, keys.Refresh):
+			m.loading = true
+			return m, m.loadHistoryCmd()
+
+		case key.Matches(msg, keys.Edit):
+			formModel := NewContactFormModel(&m.contact)
+			if m.windowWidth > 0 {
+				updatedModel, _ := formModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+				formModel = updatedModel.(ContactFormModel)
+			}
+			return formModel, formModel.Init()
+
+		case key.Matches(msg, keys.Open):
+			if item, ok := m.list.SelectedItem().(contactChatItem); ok {
+				return m.openChat(item.activity.Chat)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Chat):
+			if m.loading {
+				return m, nil
+			}
+			for _, activity := range m.history.Chats {
+				if !activity.Chat.IsGroup {
+					return m.openChat(activity.Chat)
+				}
+			}
+			return m.startConversation()
+		}
+
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m ContactDetailModel) backToContacts() (tea.Model, tea.Cmd) {
+	contactsModel := NewContactsListModel()
+	if m.windowWidth > 0 {
+		updatedModel, _ := contactsModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+		contactsModel = updatedModel.(ContactsListModel)
+	}
+	return contactsModel, contactsModel.Init()
+}
+
+func (m ContactDetailModel) openChat(chat models.Chat) (tea.Model, tea.Cmd) {
+	messagesModel := NewMessagesModel(chat, defaultConversationFilters())
+	if m.windowWidth > 0 {
+		updatedModel, _ := messagesModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+		messagesModel = updatedModel.(MessagesModel)
+	}
+	return messagesModel, messagesModel.Init()
+}
+
+// startConversation opens the new conversation screen with the contact's first handle
+// already added as the recipient.
+func (m ContactDetailModel) startConversation() (tea.Model, tea.Cmd) {
+	handles := m.handles()
+	if len(handles) == 0 {
+		m.err = fmt.Errorf("%s has no phone number or email", m.contact.Name)
+		return m, nil
+	}
+
+	newConvModel := NewNewConversationModel(defaultConversationFilters())
+	newConvModel.recipients = []recipient{{name: m.contact.Name, handle: handles[0]}}
+	newConvModel.setFocus(1)
+	if m.windowWidth > 0 {
+		updatedModel, _ := newConvModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+		newConvModel = updatedModel.(NewConversationModel)
+	}
+	return newConvModel, newConvModel.Init()
+}
+
+func (m ContactDetailModel) header() string {
+	var b strings.Builder
+
+	title := m.contact.Name
+	if m.contact.Favorite {
+		title = "★ " + title
+	}
+	b.WriteString(titleStyle.Render(title) + "\n\n")
+
+	details := m.contact
+	details.PhoneNumbers = nil
+	details.Emails = nil
+	b.WriteString(renderContactDetails(details, "  "))
+
+	b.WriteString("\n" + normalStyle.Render("Handles:") + "\n")
+	handles := m.history.Handles
+	if len(handles) == 0 {
+		for _, handle := range m.handles() {
+			handles = append(handles, models.HandleActivity{Handle: handle})
+		}
+	}
+	if len(handles) == 0 {
+		b.WriteString(helpStyle.Render("  No phone numbers or emails") + "\n")
+	}
+	for _, h := range handles {
+		line := "  " + withLabel(h.Handle, m.contact.Label(h.Handle))
+		if !m.loading {
+			if h.MessageCount > 0 {
+				line += fmt.Sprintf(" • %d messages • last %s", h.MessageCount, formatTimeAgo(h.LastTime))
+			} else {
+				line += " • no messages"
+			}
+		}
+		b.WriteString(helpStyle.Render(line) + "\n")
+	}
+
+	if !m.loading && m.err == nil {
+		total := 0
+		var first, last time.Time
+		for _, h := range m.history.Handles {
+			total += h.MessageCount
+			if !h.FirstTime.IsZero() && (first.IsZero() || h.FirstTime.Before(first)) {
+				first = h.FirstTime
+			}
+			if h.LastTime.After(last) {
+				last = h.LastTime
+			}
+		}
+		b.WriteString("\n")
+		if total > 0 {
+			b.WriteString(statusStyle.Render(fmt.Sprintf("  %d messages exchanged • %s", total, formatDateRange(first, last))) + "\n")
+		} else {
+			b.WriteString(statusStyle.Render("  No messages yet") + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+func (m ContactDetailModel) View() string {
+	if m.showHelp {
+		return helpOverlay(m.contact.Name, m.windowWidth,
+			[]key.Binding{keys.Up, keys.Down, relabel(keys.Open, "open chat"), keys.Chat},
+			[]key.Binding{keys.Edit, keys.Refresh, keys.Back, keys.Quit, keys.Help},
+		)
+	}
+
+	s := m.header() + "\n"
+
+	switch {
+	case m.loading:
+		s += normalStyle.Render("  Loading history...") + "\n\n"
+	case m.err != nil:
+		s += errorStyle.Render(fmt.Sprintf("  Error: %v", m.err)) + "\n\n"
+	case len(m.history.Chats) == 0:
+		s += normalStyle.Render("  No conversations yet") + "\n\n"
+	default:
+		s += m.list.View() + "\n"
+	}
+
+	s += helpView(m.windowWidth, keys.Help, relabel(keys.Open, "open chat"), keys.Chat, keys.Edit, keys.Refresh, keys.Back, keys.Quit)
+	return s
+}
+
+// formatDateRange renders the first and last contact dates, e.g. "Mar 2021 – 2h ago".
+func formatDateRange(first, last time.Time) string {
+	if first.IsZero() {
+		return formatTimeAgo(last)
+	}
+	return fmt.Sprintf("%s – %s", first.Format("Jan 2006"), formatTimeAgo(last))
+}
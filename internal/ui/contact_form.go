@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -15,16 +17,63 @@ type contactSavedMsg struct {
 	err     error
 }
 
+// Indexes into ContactFormModel.detailInputs.
+const (
+	detailNickname = iota
+	detailOrganization
+	detailBirthday
+	detailTags
+	detailNotes
+	detailCount
+)
+
+var detailLabels = [detailCount]string{"Nickname:", "Organisation:", "Birthday:", "Tags:", "Notes:"}
+
+type formSection int
+
+const (
+	sectionName formSection = iota
+	sectionPhone
+	sectionEmail
+	sectionDetail
+)
+
+// contactField is one phone number or email row in the contact form.
+type contactField struct {
+	input textinput.Model
+	label string
+	err   error
+}
+
+func newContactField(section formSection, value, label string) contactField {
+	input := textinput.New()
+	input.Width = 32
+	if section == sectionPhone {
+		input.Placeholder = "Phone number"
+		input.CharLimit = 30
+	} else {
+		input.Placeholder = "Email address"
+		input.CharLimit = 100
+	}
+	input.SetValue(value)
+	return contactField{input: input, label: label}
+}
+
 type ContactFormModel struct {
 	originalContact *contacts.Contact
 	nameInput       textinput.Model
-	phoneInputs     []textinput.Model
-	emailInputs     []textinput.Model
+	phoneFields     []contactField
+	emailFields     []contactField
+	detailInputs    []textinput.Model
+	favorite        bool
 	focusIndex      int
 	err             error
 	saved           bool
 	windowWidth     int
 	windowHeight    int
+	// owners maps the canonical phone numbers and emails of the other saved contacts to
+	// the contact that has them, loaded once for the duplicate checks in validateFields.
+	owners map[string]contacts.Contact
 }
 
 // NewContactFormModel creates a form for adding or editing a contact.
@@ -35,44 +84,56 @@ func NewContactFormModel(contact *contacts.Contact) ContactFormModel {
 	nameInput.CharLimit = 100
 	nameInput.Width = 50
 
-	phoneInputs := make([]textinput.Model, 3)
-	for i := range phoneInputs {
-		phoneInputs[i] = textinput.New()
-		phoneInputs[i].Placeholder = fmt.Sprintf("Phone Number %d (optional)", i+1)
-		phoneInputs[i].CharLimit = 20
-		phoneInputs[i].Width = 50
-	}
-
-	emailInputs := make([]textinput.Model, 3)
-	for i := range emailInputs {
-		emailInputs[i] = textinput.New()
-		emailInputs[i].Placeholder = fmt.Sprintf("Email %d (optional)", i+1)
-		emailInputs[i].CharLimit = 100
-		emailInputs[i].Width = 50
+	detailInputs := make([]textinput.Model, detailCount)
+	for i := range detailInputs {
+		detailInputs[i] = textinput.New()
+		detailInputs[i].CharLimit = 100
+		detailInputs[i].Width = 50
 	}
+	detailInputs[detailNickname].Placeholder = "Shown in conversations instead of the name (optional)"
+	detailInputs[detailOrganization].Placeholder = "Company or organisation (optional)"
+	detailInputs[detailBirthday].Placeholder = "YYYY-MM-DD or --MM-DD (optional)"
+	detailInputs[detailBirthday].CharLimit = 10
+	detailInputs[detailTags].Placeholder = "Comma-separated, e.g. family, oncall (optional)"
+	detailInputs[detailNotes].Placeholder = "Notes (optional)"
+	detailInputs[detailNotes].CharLimit = 500
 
 	m := ContactFormModel{
 		originalContact: contact,
 		nameInput:       nameInput,
-		phoneInputs:     phoneInputs,
-		emailInputs:     emailInputs,
+		detailInputs:    detailInputs,
 		focusIndex:      0,
 	}
 
 	if contact != nil {
 		m.nameInput.SetValue(contact.Name)
-		for i, phone := range contact.PhoneNumbers {
-			if i < len(m.phoneInputs) {
-				m.phoneInputs[i].SetValue(phone)
-			}
+		m.detailInputs[detailNickname].SetValue(contact.Nickname)
+		m.detailInputs[detailOrganization].SetValue(contact.Organization)
+		m.detailInputs[detailBirthday].SetValue(contact.Birthday)
+		m.detailInputs[detailTags].SetValue(strings.Join(contact.Tags, ", "))
+		m.detailInputs[detailNotes].SetValue(contact.Notes)
+		m.favorite = contact.Favorite
+		for _, phone := range contact.PhoneNumbers {
+			m.phoneFields = append(m.phoneFields, newContactField(sectionPhone, phone, contact.Label(phone)))
 		}
-		for i, email := range contact.Emails {
-			if i < len(m.emailInputs) {
-				m.emailInputs[i].SetValue(email)
-			}
+		for _, email := range contact.Emails {
+			m.emailFields = append(m.emailFields, newContactField(sectionEmail, email, contact.Label(email)))
 		}
 	}
 
+	if len(m.phoneFields) == 0 {
+		m.phoneFields = append(m.phoneFields, newContactField(sectionPhone, "", ""))
+	}
+	if len(m.emailFields) == 0 {
+		m.emailFields = append(m.emailFields, newContactField(sectionEmail, "", ""))
+	}
+	exceptID := ""
+	if contact != nil {
+		exceptID = contact.ID
+	}
+	m.owners, _ = contacts.IdentifierOwners(exceptID)
+	m.validateFields()
+
 	return m
 }
 
@@ -88,11 +149,11 @@ func (m ContactFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
+		if key.Matches(msg, keys.ForceQuit) {
 			return m, tea.Quit
 		}
 
-		if msg.String() == "esc" {
+		if key.Matches(msg, keys.Back) {
 			contactsModel := NewContactsListModel()
 			if m.windowWidth > 0 {
 				updatedModel, _ := contactsModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
@@ -101,10 +162,10 @@ func (m ContactFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return contactsModel, contactsModel.Init()
 		}
 
-		if msg.String() == "tab" || msg.String() == "shift+tab" || msg.String() == "down" || msg.String() == "up" {
-			totalInputs := 1 + len(m.phoneInputs) + len(m.emailInputs)
+		if key.Matches(msg, keys.NextField, keys.PrevField) {
+			totalInputs := m.inputCount()
 
-			if msg.String() == "up" || msg.String() == "shift+tab" {
+			if key.Matches(msg, keys.PrevField) {
 				m.focusIndex--
 				if m.focusIndex < 0 {
 					m.focusIndex = totalInputs - 1
@@ -120,10 +181,36 @@ func (m ContactFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		if msg.String() == "ctrl+s" {
+		if key.Matches(msg, keys.Save) {
+			if invalid := m.validateFields(); invalid > 0 {
+				m.err = fmt.Errorf("%d field(s) need fixing before saving", invalid)
+				return m, nil
+			}
 			return m, m.saveContact()
 		}
 
+		if key.Matches(msg, keys.Favourite) {
+			m.favorite = !m.favorite
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.AddField) {
+			m.addField()
+			return m, textinput.Blink
+		}
+
+		if key.Matches(msg, keys.RemoveField) {
+			m.removeField()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.CycleLabel) {
+			if field := m.focusedField(); field != nil {
+				field.label = nextLabel(field.label)
+			}
+			return m, nil
+		}
+
 	case contactSavedMsg:
 		if msg.success {
 			contactsModel := NewContactsListModel()
@@ -134,30 +221,181 @@ func (m ContactFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return contactsModel, contactsModel.Init()
 		}
 		m.err = msg.err
+		var invalid *contacts.ValidationError
+		if errors.As(msg.err, &invalid) {
+			for _, fields := range [][]contactField{m.phoneFields, m.emailFields} {
+				for i := range fields {
+					if err := invalid.For(fields[i].input.Value()); err != nil {
+						fields[i].err = err
+					}
+				}
+			}
+			m.err = fmt.Errorf("%d field(s) need fixing before saving", len(invalid.Invalid))
+		}
 		return m, nil
 	}
 
 	cmd := m.updateInputs(msg)
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.validateFields()
+	}
 	return m, cmd
 }
 
+func (m ContactFormModel) inputCount() int {
+	return 1 + len(m.phoneFields) + len(m.emailFields) + len(m.detailInputs)
+}
+
+// focusedSection returns the section holding the focused input and its index within that section.
+func (m ContactFormModel) focusedSection() (formSection, int) {
+	index := m.focusIndex
+	if index == 0 {
+		return sectionName, 0
+	}
+	index--
+	if index < len(m.phoneFields) {
+		return sectionPhone, index
+	}
+	index -= len(m.phoneFields)
+	if index < len(m.emailFields) {
+		return sectionEmail, index
+	}
+	return sectionDetail, index - len(m.emailFields)
+}
+
+// focusedField returns the focused phone or email field, or nil if another input has focus.
+func (m *ContactFormModel) focusedField() *contactField {
+	section, index := m.focusedSection()
+	switch section {
+	case sectionPhone:
+		return &m.phoneFields[index]
+	case sectionEmail:
+		return &m.emailFields[index]
+	}
+	return nil
+}
+
+// addField appends an email field when an email or detail input has focus and a phone field
+// otherwise, then focuses it.
+func (m *ContactFormModel) addField() {
+	section, _ := m.focusedSection()
+	if section == sectionEmail || section == sectionDetail {
+		m.emailFields = append(m.emailFields, newContactField(sectionEmail, "", ""))
+		m.focusIndex = len(m.phoneFields) + len(m.emailFields)
+	} else {
+		m.phoneFields = append(m.phoneFields, newContactField(sectionPhone, "", ""))
+		m.focusIndex = len(m.phoneFields)
+	}
+	m.updateFocus()
+}
+
+// removeField deletes the focused phone or email field. The last field of each kind is
+// cleared rather than removed so there is always somewhere to type.
+func (m *ContactFormModel) removeField() {
+	section, index := m.focusedSection()
+	fields := &m.phoneFields
+	if section == sectionEmail {
+		fields = &m.emailFields
+	} else if section != sectionPhone {
+		return
+	}
+
+	if len(*fields) == 1 {
+		(*fields)[0] = newContactField(section, "", "")
+	} else {
+		*fields = append((*fields)[:index], (*fields)[index+1:]...)
+		if index == len(*fields) {
+			m.focusIndex--
+		}
+	}
+	m.validateFields()
+	m.updateFocus()
+}
+
+// nextLabel cycles through no label and contacts.StandardLabels.
+func nextLabel(label string) string {
+	options := append([]string{""}, contacts.StandardLabels...)
+	for i, option := range options {
+		if option == label {
+			return options[(i+1)%len(options)]
+		}
+	}
+	return options[0]
+}
+
+// validateFields checks every non-empty phone and email field, including for duplicates
+// within the contact and numbers or emails added that belong to another contact, and
+// returns how many are invalid.
+func (m *ContactFormModel) validateFields() int {
+	invalid := 0
+	seen := make(map[string]string)
+
+	original := make(map[string]bool)
+	if m.originalContact != nil {
+		for _, identifier := range append(append([]string{}, m.originalContact.PhoneNumbers...), m.originalContact.Emails...) {
+			original[m.originalContact.Canonical(identifier)] = true
+		}
+	}
+
+	check := func(fields []contactField, name string, validate func(string) error, key func(string) string) {
+		for i := range fields {
+			value := strings.TrimSpace(fields[i].input.Value())
+			fields[i].err = nil
+			if value == "" {
+				continue
+			}
+
+			if err := validate(value); err != nil {
+				fields[i].err = err
+			} else if other, ok := seen[key(value)]; ok {
+				fields[i].err = fmt.Errorf("duplicate of %s", other)
+			} else if owner, ok := m.owners[contacts.NormalizeIdentifier(value)]; ok && !original[key(value)] {
+				fields[i].err = fmt.Errorf("already belongs to %s", owner.Name)
+				seen[key(value)] = fmt.Sprintf("%s %d", name, i+1)
+			} else {
+				seen[key(value)] = fmt.Sprintf("%s %d", name, i+1)
+			}
+
+			if fields[i].err != nil {
+				invalid++
+			}
+		}
+	}
+
+	check(m.phoneFields, "Phone", contacts.ValidatePhoneNumber, func(s string) string {
+		return contacts.NormalizePhoneNumber(s, contacts.DefaultRegion())
+	})
+	check(m.emailFields, "Email", contacts.ValidateEmail, strings.ToLower)
+
+	if err := contacts.ValidateBirthday(strings.TrimSpace(m.detailInputs[detailBirthday].Value())); err != nil {
+		invalid++
+	}
+
+	return invalid
+}
+
 func (m *ContactFormModel) updateFocus() {
 	m.nameInput.Blur()
-	for i := range m.phoneInputs {
-		m.phoneInputs[i].Blur()
+	for i := range m.phoneFields {
+		m.phoneFields[i].input.Blur()
 	}
-	for i := range m.emailInputs {
-		m.emailInputs[i].Blur()
+	for i := range m.emailFields {
+		m.emailFields[i].input.Blur()
+	}
+	for i := range m.detailInputs {
+		m.detailInputs[i].Blur()
 	}
 
-	if m.focusIndex == 0 {
+	switch section, index := m.focusedSection(); section {
+	case sectionName:
 		m.nameInput.Focus()
-	} else if m.focusIndex <= len(m.phoneInputs) {
-		m.phoneInputs[m.focusIndex-1].Focus()
-	} else {
-		emailIndex := m.focusIndex - 1 - len(m.phoneInputs)
-		if emailIndex < len(m.emailInputs) {
-			m.emailInputs[emailIndex].Focus()
+	case sectionPhone:
+		m.phoneFields[index].input.Focus()
+	case sectionEmail:
+		m.emailFields[index].input.Focus()
+	case sectionDetail:
+		if index < len(m.detailInputs) {
+			m.detailInputs[index].Focus()
 		}
 	}
 }
@@ -169,13 +407,18 @@ func (m *ContactFormModel) updateInputs(msg tea.Msg) tea.Cmd {
 	m.nameInput, cmd = m.nameInput.Update(msg)
 	cmds = append(cmds, cmd)
 
-	for i := range m.phoneInputs {
-		m.phoneInputs[i], cmd = m.phoneInputs[i].Update(msg)
+	for i := range m.phoneFields {
+		m.phoneFields[i].input, cmd = m.phoneFields[i].input.Update(msg)
 		cmds = append(cmds, cmd)
 	}
 
-	for i := range m.emailInputs {
-		m.emailInputs[i], cmd = m.emailInputs[i].Update(msg)
+	for i := range m.emailFields {
+		m.emailFields[i].input, cmd = m.emailFields[i].input.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	for i := range m.detailInputs {
+		m.detailInputs[i], cmd = m.detailInputs[i].Update(msg)
 		cmds = append(cmds, cmd)
 	}
 
@@ -189,19 +432,27 @@ func (m ContactFormModel) saveContact() tea.Cmd {
 			return contactSavedMsg{success: false, err: fmt.Errorf("name is required")}
 		}
 
+		labels := make(map[string]string)
+
 		phoneNumbers := []string{}
-		for _, input := range m.phoneInputs {
-			phone := strings.TrimSpace(input.Value())
+		for _, field := range m.phoneFields {
+			phone := strings.TrimSpace(field.input.Value())
 			if phone != "" {
 				phoneNumbers = append(phoneNumbers, phone)
+				if field.label != "" {
+					labels[phone] = field.label
+				}
 			}
 		}
 
 		emails := []string{}
-		for _, input := range m.emailInputs {
-			email := strings.TrimSpace(input.Value())
+		for _, field := range m.emailFields {
+			email := strings.TrimSpace(field.input.Value())
 			if email != "" {
 				emails = append(emails, email)
+				if field.label != "" {
+					labels[email] = field.label
+				}
 			}
 		}
 
@@ -209,17 +460,28 @@ func (m ContactFormModel) saveContact() tea.Cmd {
 			return contactSavedMsg{success: false, err: fmt.Errorf("at least one phone number or email is required")}
 		}
 
-		if m.originalContact != nil && m.originalContact.Name != name {
-			if err := contacts.DeleteContact(m.originalContact.Name); err != nil {
-				return contactSavedMsg{success: false, err: fmt.Errorf("failed to delete old contact: %w", err)}
-			}
+		birthday := strings.TrimSpace(m.detailInputs[detailBirthday].Value())
+		if err := contacts.ValidateBirthday(birthday); err != nil {
+			return contactSavedMsg{success: false, err: err}
 		}
 
-		contact := contacts.Contact{
-			Name:         name,
-			PhoneNumbers: phoneNumbers,
-			Emails:       emails,
+		contact := contacts.Contact{}
+		if m.originalContact != nil {
+			contact = *m.originalContact
+		}
+		contact.Name = name
+		contact.PhoneNumbers = phoneNumbers
+		contact.Emails = emails
+		contact.Labels = nil
+		if len(labels) > 0 {
+			contact.Labels = labels
 		}
+		contact.Nickname = strings.TrimSpace(m.detailInputs[detailNickname].Value())
+		contact.Organization = strings.TrimSpace(m.detailInputs[detailOrganization].Value())
+		contact.Birthday = birthday
+		contact.Tags = contacts.ParseTags(m.detailInputs[detailTags].Value())
+		contact.Notes = strings.TrimSpace(m.detailInputs[detailNotes].Value())
+		contact.Favorite = m.favorite
 
 		if err := contacts.SaveContact(contact); err != nil {
 			return contactSavedMsg{success: false, err: err}
@@ -239,36 +501,64 @@ func (m ContactFormModel) View() string {
 
 	b.WriteString(titleStyle.Render(title) + "\n\n")
 
-	focusedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
-	blurredStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	focusedStyle := lipgloss.NewStyle().Foreground(accentColor)
+	blurredStyle := lipgloss.NewStyle().Foreground(dimColor)
 
-	renderInput := func(input textinput.Model, label string, focused bool) {
-		style := blurredStyle
+	labelStyle := func(focused bool) lipgloss.Style {
 		if focused {
-			style = focusedStyle
+			return focusedStyle
 		}
-		b.WriteString(style.Render(label) + "\n")
-		b.WriteString(input.View() + "\n\n")
+		return blurredStyle
 	}
 
-	renderInput(m.nameInput, "Name (required):", m.focusIndex == 0)
+	b.WriteString(labelStyle(m.focusIndex == 0).Render("Name (required):") + "\n")
+	b.WriteString(m.nameInput.View() + "\n\n")
 
-	b.WriteString(normalStyle.Render("Phone Numbers:") + "\n")
-	for i, input := range m.phoneInputs {
-		renderInput(input, fmt.Sprintf("  Phone %d:", i+1), m.focusIndex == i+1)
+	renderFields := func(fields []contactField, name string, offset int) {
+		for i, field := range fields {
+			label := ""
+			if field.label != "" {
+				label = "[" + field.label + "]"
+			}
+			line := labelStyle(m.focusIndex == offset+i).Render(fmt.Sprintf("  %s %-3d %-9s", name, i+1, label))
+			line += field.input.View()
+			if field.err != nil {
+				line += "  " + errorStyle.Render("✗ "+field.err.Error())
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
 	}
 
+	b.WriteString(normalStyle.Render("Phone Numbers:") + "\n")
+	renderFields(m.phoneFields, "Phone", 1)
+
 	b.WriteString(normalStyle.Render("Email Addresses:") + "\n")
-	for i, input := range m.emailInputs {
-		emailIndex := i + 1 + len(m.phoneInputs)
-		renderInput(input, fmt.Sprintf("  Email %d:", i+1), m.focusIndex == emailIndex)
+	renderFields(m.emailFields, "Email", 1+len(m.phoneFields))
+
+	b.WriteString(normalStyle.Render("Details:") + "\n")
+	for i, input := range m.detailInputs {
+		detailIndex := i + 1 + len(m.phoneFields) + len(m.emailFields)
+		line := labelStyle(m.focusIndex == detailIndex).Render(fmt.Sprintf("  %-14s", detailLabels[i])) + input.View()
+		if i == detailBirthday {
+			if err := contacts.ValidateBirthday(strings.TrimSpace(input.Value())); err != nil {
+				line += "  " + errorStyle.Render("✗ "+err.Error())
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+
+	favorite := "☆ Not a favourite"
+	if m.favorite {
+		favorite = "★ Favourite"
 	}
+	b.WriteString("\n" + normalStyle.Render(favorite) + "\n\n")
 
 	if m.err != nil {
 		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n")
 	}
 
-	b.WriteString(helpStyle.Render("tab/↑↓: navigate • ctrl+s: save • esc: cancel"))
+	b.WriteString(helpView(m.windowWidth, keys.NextField, keys.PrevField, keys.AddField, keys.RemoveField, keys.CycleLabel, keys.Favourite, keys.Save, relabel(keys.Back, "cancel")))
 
 	return b.String()
 }
@@ -2,10 +2,15 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
+	"github.com/saravenpi/chime/internal/config"
 	"github.com/saravenpi/chime/internal/contacts"
 )
 
@@ -13,8 +18,31 @@ type contactItem struct {
 	contact contacts.Contact
 }
 
-func (i contactItem) FilterValue() string { return i.contact.Name }
-func (i contactItem) Title() string       { return i.contact.Name }
+func (i contactItem) FilterValue() string {
+	value := i.contact.Name
+	if i.contact.Nickname != "" {
+		value += " " + i.contact.Nickname
+	}
+	if i.contact.Organization != "" {
+		value += " " + i.contact.Organization
+	}
+	for _, tag := range i.contact.Tags {
+		value += " #" + tag
+	}
+	return value
+}
+
+func (i contactItem) Title() string {
+	title := i.contact.Name
+	if i.contact.Nickname != "" {
+		title += fmt.Sprintf(" (%s)", i.contact.Nickname)
+	}
+	if i.contact.Favorite {
+		title = "★ " + title
+	}
+	return title
+}
+
 func (i contactItem) Description() string {
 	desc := ""
 	if len(i.contact.PhoneNumbers) > 0 {
@@ -26,45 +54,106 @@ func (i contactItem) Description() string {
 		}
 		desc += i.contact.Emails[0]
 	}
+	if len(i.contact.Tags) > 0 {
+		if desc != "" {
+			desc += " • "
+		}
+		desc += "#" + strings.Join(i.contact.Tags, " #")
+	}
 	return desc
 }
 
+// ContactsChangedMsg is sent to the program when contact files change on disk,
+// so open views can reload contacts and re-resolve display names.
+type ContactsChangedMsg struct{}
+
 type contactsLoadedMsg struct {
-	contacts []contacts.Contact
-	err      error
+	contacts   []contacts.Contact
+	tags       []string
+	duplicates int
+	err        error
+}
+
+type vcardDoneMsg struct {
+	status string
+	err    error
+}
+
+// importPlannedMsg carries the plan for a CSV import, shown for confirmation before anything
+// is saved.
+type importPlannedMsg struct {
+	preview *importPreview
+	err     error
+}
+
+type importPreview struct {
+	path     string
+	preset   string
+	plan     contacts.ImportPlan
+	warnings []string
 }
 
+type vcardPromptMode int
+
+const (
+	vcardPromptNone vcardPromptMode = iota
+	vcardPromptImport
+	vcardPromptExportOne
+	vcardPromptExportAll
+)
+
 type ContactsListModel struct {
-	list                list.Model
-	contacts            []contacts.Contact
-	loading             bool
-	err                 error
-	windowWidth         int
-	windowHeight        int
-	confirmDelete       bool
-	contactToDelete     *contacts.Contact
+	list            list.Model
+	contacts        []contacts.Contact
+	loading         bool
+	err             error
+	windowWidth     int
+	windowHeight    int
+	confirmDelete   bool
+	contactToDelete *contacts.Contact
+	vcardPrompt     vcardPromptMode
+	vcardPathInput  textinput.Model
+	vcardExport     []contacts.Contact
+	status          string
+	tags            []string
+	tagFilter       string
+	duplicates      int
+	importPreview   *importPreview
+	importView      viewport.Model
+	showHelp        bool
 }
 
 // NewContactsListModel creates a new contacts list view.
 func NewContactsListModel() ContactsListModel {
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
-		Foreground(lipgloss.Color("5")).
+		Foreground(accentColor).
 		Bold(true)
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
-		Foreground(lipgloss.Color("8"))
+		Foreground(dimColor)
 
 	l := list.New([]list.Item{}, delegate, 80, 20)
 	l.Title = "Contacts"
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
 	l.SetShowHelp(false)
+	l.KeyMap = listKeyMap()
+
+	pathInput := textinput.New()
+	pathInput.CharLimit = 500
+	pathInput.Width = 60
+
+	importView := viewport.New(80, 20)
+	importView.KeyMap.Up = keys.Up
+	importView.KeyMap.Down = keys.Down
 
 	return ContactsListModel{
-		list:         l,
-		loading:      true,
-		windowWidth:  80,
-		windowHeight: 30,
+		list:           l,
+		vcardPathInput: pathInput,
+		importView:     importView,
+		loading:        true,
+		windowWidth:    80,
+		windowHeight:   30,
 	}
 }
 
@@ -74,14 +163,136 @@ func (m ContactsListModel) Init() tea.Cmd {
 
 func (m ContactsListModel) loadContactsCmd() tea.Cmd {
 	return func() tea.Msg {
-		contacts, err := contacts.ListContacts()
+		all, err := contacts.ListContacts()
 		if err != nil {
 			return contactsLoadedMsg{contacts: nil, err: err}
 		}
-		return contactsLoadedMsg{contacts: contacts, err: nil}
+		tags, err := contacts.ListTags()
+		if err != nil {
+			return contactsLoadedMsg{contacts: nil, err: err}
+		}
+		return contactsLoadedMsg{contacts: all, tags: tags, duplicates: len(contacts.FindDuplicates(all)), err: nil}
 	}
 }
 
+// openVCardPrompt asks for a .vcf or .csv path to import from or export to.
+func (m *ContactsListModel) openVCardPrompt(mode vcardPromptMode, defaultPath string) tea.Cmd {
+	m.vcardPrompt = mode
+	m.status = ""
+	m.err = nil
+	m.vcardPathInput.SetValue(defaultPath)
+	m.vcardPathInput.CursorEnd()
+	m.vcardPathInput.Focus()
+	return textinput.Blink
+}
+
+func (m ContactsListModel) vcardCmd(mode vcardPromptMode, path string, toExport []contacts.Contact) tea.Cmd {
+	return func() tea.Msg {
+		path = config.ExpandHome(path)
+		isCSV := strings.EqualFold(filepath.Ext(path), ".csv")
+		if mode == vcardPromptImport && isCSV {
+			parsed, warnings, preset, err := contacts.ReadCSVFile(path, "", nil)
+			if err != nil {
+				return importPlannedMsg{err: err}
+			}
+			plan, err := contacts.PlanImport(parsed)
+			if err != nil {
+				return importPlannedMsg{err: err}
+			}
+			return importPlannedMsg{preview: &importPreview{path: path, preset: preset, plan: plan, warnings: warnings}}
+		}
+		if mode == vcardPromptImport {
+			imported, skipped, warnings, err := contacts.ImportVCardFile(path)
+			if err != nil {
+				return vcardDoneMsg{err: err}
+			}
+			if len(warnings) > 0 {
+				return vcardDoneMsg{status: fmt.Sprintf("Imported %d contacts (%d already present, %d malformed lines skipped)", imported, skipped, len(warnings))}
+			}
+			return vcardDoneMsg{status: fmt.Sprintf("Imported %d contacts (%d already present)", imported, skipped)}
+		}
+
+		export := contacts.ExportVCardFile
+		if isCSV {
+			export = contacts.ExportCSVFile
+		}
+		if err := export(path, toExport); err != nil {
+			return vcardDoneMsg{err: err}
+		}
+		return vcardDoneMsg{status: fmt.Sprintf("Exported %d contacts to %s", len(toExport), path)}
+	}
+}
+
+func (m ContactsListModel) applyImportCmd(plan contacts.ImportPlan) tea.Cmd {
+	return func() tea.Msg {
+		if err := contacts.ApplyImportPlan(plan); err != nil {
+			return vcardDoneMsg{err: err}
+		}
+		return vcardDoneMsg{status: fmt.Sprintf("Created %d contacts and updated %d (%d unchanged)", plan.Created(), plan.Merged(), plan.Unchanged)}
+	}
+}
+
+// content lists what the import will do: new contacts, what each merge adds to
+// an existing contact, and every warning.
+func (p importPreview) content() string {
+	var b strings.Builder
+	for _, change := range p.plan.Changes {
+		if change.Existing == nil {
+			identifiers := append(append([]string{}, change.Contact.PhoneNumbers...), change.Contact.Emails...)
+			b.WriteString(normalStyle.Render(fmt.Sprintf("+ %s (%s)", change.Contact.Name, strings.Join(identifiers, ", "))) + "\n")
+		} else {
+			b.WriteString(normalStyle.Render(fmt.Sprintf("~ %s: add %s", change.Existing.Name, contacts.ImportSummary(*change.Existing, change.Contact))) + "\n")
+		}
+	}
+	for _, warning := range append(append([]string{}, p.warnings...), p.plan.Warnings...) {
+		b.WriteString(errorStyle.Render("! "+warning) + "\n")
+	}
+	return b.String()
+}
+
+func (m ContactsListModel) updateImportPreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Confirm, keys.Submit):
+		plan := m.importPreview.plan
+		m.importPreview = nil
+		m.loading = true
+		return m, m.applyImportCmd(plan)
+
+	case key.Matches(msg, keys.Deny, keys.Back):
+		m.importPreview = nil
+		m.status = "Import cancelled"
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.importView, cmd = m.importView.Update(msg)
+	return m, cmd
+}
+
+func (m ContactsListModel) updateVCardPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back):
+		m.vcardPrompt = vcardPromptNone
+		m.vcardPathInput.Blur()
+		return m, nil
+
+	case key.Matches(msg, keys.Submit):
+		path := strings.TrimSpace(m.vcardPathInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		mode := m.vcardPrompt
+		m.vcardPrompt = vcardPromptNone
+		m.vcardPathInput.Blur()
+		m.loading = true
+		return m, m.vcardCmd(mode, path, m.vcardExport)
+	}
+
+	var cmd tea.Cmd
+	m.vcardPathInput, cmd = m.vcardPathInput.Update(msg)
+	return m, cmd
+}
+
 func (m ContactsListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -89,6 +300,8 @@ func (m ContactsListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.windowHeight = msg.Height
 		m.list.SetWidth(msg.Width)
 		m.list.SetHeight(msg.Height - 4)
+		m.importView.Width = msg.Width
+		m.importView.Height = max(msg.Height-8, 3)
 		return m, nil
 
 	case contactsLoadedMsg:
@@ -98,24 +311,59 @@ func (m ContactsListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		m.err = nil
 		m.contacts = msg.contacts
-		items := make([]list.Item, len(m.contacts))
-		for i, contact := range m.contacts {
-			items[i] = contactItem{contact: contact}
+		m.tags = msg.tags
+		m.duplicates = msg.duplicates
+		if m.tagFilter != "" && !containsFold(m.tags, m.tagFilter) {
+			m.tagFilter = ""
 		}
-		m.list.SetItems(items)
-		m.list.Title = fmt.Sprintf("Contacts - %d total", len(m.contacts))
+		m.applyTagFilter()
 		return m, nil
 
+	case ContactsChangedMsg:
+		return m, m.loadContactsCmd()
+
+	case importPlannedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = ""
+			m.err = msg.err
+			return m, nil
+		}
+		m.importPreview = msg.preview
+		m.importView.SetContent(msg.preview.content())
+		m.importView.GotoTop()
+		return m, nil
+
+	case vcardDoneMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = ""
+			m.err = msg.err
+			return m, nil
+		}
+		m.status = msg.status
+		m.loading = true
+		return m, m.loadContactsCmd()
+
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
+		if key.Matches(msg, keys.ForceQuit) {
 			return m, tea.Quit
 		}
 
+		if m.vcardPrompt != vcardPromptNone {
+			return m.updateVCardPrompt(msg)
+		}
+
+		if m.importPreview != nil {
+			return m.updateImportPreview(msg)
+		}
+
 		if m.confirmDelete {
-			if msg.String() == "y" || msg.String() == "Y" {
+			if key.Matches(msg, keys.Confirm) {
 				if m.contactToDelete != nil {
-					if err := contacts.DeleteContact(m.contactToDelete.Name); err == nil {
+					if err := contacts.DeleteContact(m.contactToDelete.ID); err == nil {
 						m.confirmDelete = false
 						m.contactToDelete = nil
 						m.loading = true
@@ -126,7 +374,7 @@ func (m ContactsListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.contactToDelete = nil
 				return m, nil
 			}
-			if msg.String() == "n" || msg.String() == "N" || msg.String() == "esc" {
+			if key.Matches(msg, keys.Deny, keys.Back) {
 				m.confirmDelete = false
 				m.contactToDelete = nil
 				return m, nil
@@ -134,30 +382,93 @@ func (m ContactsListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		if msg.String() == "esc" || msg.String() == "q" {
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		if m.list.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, keys.Back):
 			menuModel := NewMenuModel()
 			if m.windowWidth > 0 {
 				updatedModel, _ := menuModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 				menuModel = updatedModel.(MenuModel)
 			}
 			return menuModel, menuModel.Init()
-		}
 
-		if msg.String() == "n" || msg.String() == "a" {
+		case key.Matches(msg, keys.New):
 			formModel := NewContactFormModel(nil)
 			if m.windowWidth > 0 {
 				updatedModel, _ := formModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 				formModel = updatedModel.(ContactFormModel)
 			}
 			return formModel, formModel.Init()
-		}
 
-		if msg.String() == "r" {
+		case key.Matches(msg, keys.Refresh):
 			m.loading = true
 			return m, m.loadContactsCmd()
-		}
 
-		if msg.String() == "enter" && len(m.contacts) > 0 {
+		case key.Matches(msg, keys.Import):
+			return m, m.openVCardPrompt(vcardPromptImport, "~/contacts.vcf")
+
+		case key.Matches(msg, keys.Export):
+			if item, ok := m.list.SelectedItem().(contactItem); ok {
+				m.vcardExport = []contacts.Contact{item.contact}
+				return m, m.openVCardPrompt(vcardPromptExportOne, "~/"+item.contact.Name+".vcf")
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.ExportAll):
+			if len(m.contacts) > 0 {
+				m.vcardExport = m.contacts
+				return m, m.openVCardPrompt(vcardPromptExportAll, "~/chime-contacts.vcf")
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.TagFilter):
+			m.cycleTagFilter()
+			return m, nil
+
+		case key.Matches(msg, keys.Duplicates):
+			duplicatesModel := NewDuplicatesModel()
+			if m.windowWidth > 0 {
+				updatedModel, _ := duplicatesModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+				duplicatesModel = updatedModel.(DuplicatesModel)
+			}
+			return duplicatesModel, duplicatesModel.Init()
+
+		case key.Matches(msg, keys.Groups):
+			groupsModel := NewGroupsModel()
+			if m.windowWidth > 0 {
+				updatedModel, _ := groupsModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+				groupsModel = updatedModel.(GroupsModel)
+			}
+			return groupsModel, groupsModel.Init()
+
+		case key.Matches(msg, keys.Open) && len(m.contacts) > 0:
+			if item, ok := m.list.SelectedItem().(contactItem); ok {
+				detailModel := NewContactDetailModel(item.contact)
+				if m.windowWidth > 0 {
+					updatedModel, _ := detailModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+					detailModel = updatedModel.(ContactDetailModel)
+				}
+				return detailModel, detailModel.Init()
+			}
+
+		case key.Matches(msg, keys.Edit) && len(m.contacts) > 0:
 			if item, ok := m.list.SelectedItem().(contactItem); ok {
 				formModel := NewContactFormModel(&item.contact)
 				if m.windowWidth > 0 {
@@ -166,14 +477,12 @@ func (m ContactsListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return formModel, formModel.Init()
 			}
-		}
 
-		if msg.String() == "d" || msg.String() == "delete" {
+		case key.Matches(msg, keys.Delete):
 			if item, ok := m.list.SelectedItem().(contactItem); ok {
 				m.confirmDelete = true
 				contactCopy := item.contact
 				m.contactToDelete = &contactCopy
-				return m, nil
 			}
 			return m, nil
 		}
@@ -186,12 +495,91 @@ func (m ContactsListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// cycleTagFilter moves the tag filter to the next tag, wrapping back to showing all contacts.
+func (m *ContactsListModel) cycleTagFilter() {
+	next := ""
+	if len(m.tags) > 0 {
+		if m.tagFilter == "" {
+			next = m.tags[0]
+		} else {
+			for i, tag := range m.tags {
+				if strings.EqualFold(tag, m.tagFilter) && i+1 < len(m.tags) {
+					next = m.tags[i+1]
+				}
+			}
+		}
+	}
+	m.tagFilter = next
+	m.applyTagFilter()
+}
+
+func (m *ContactsListModel) applyTagFilter() {
+	items := make([]list.Item, 0, len(m.contacts))
+	for _, contact := range m.contacts {
+		if m.tagFilter == "" || contact.HasTag(m.tagFilter) {
+			items = append(items, contactItem{contact: contact})
+		}
+	}
+	m.list.SetItems(items)
+
+	if m.tagFilter == "" {
+		m.list.Title = fmt.Sprintf("Contacts - %d total", len(m.contacts))
+	} else {
+		m.list.Title = fmt.Sprintf("Contacts - #%s (%d of %d)", m.tagFilter, len(items), len(m.contacts))
+	}
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m ContactsListModel) View() string {
+	if m.showHelp {
+		return helpOverlay("Contacts", m.windowWidth, m.fullHelp()...)
+	}
+
+	if m.vcardPrompt != vcardPromptNone {
+		title := "Import Contacts"
+		label := "Read contacts from (.vcf, or a Google/Outlook .csv):"
+		switch m.vcardPrompt {
+		case vcardPromptExportOne:
+			title = "Export Contact"
+			label = fmt.Sprintf("Write %s to:", m.vcardExport[0].Name)
+		case vcardPromptExportAll:
+			title = "Export All Contacts"
+			label = fmt.Sprintf("Write %d contacts to:", len(m.vcardExport))
+		}
+		s := titleStyle.Render(title) + "\n\n"
+		s += normalStyle.Render(label) + "\n"
+		s += m.vcardPathInput.View() + "\n\n"
+		s += helpView(m.windowWidth, relabel(keys.Submit, "confirm (a .csv path imports or exports CSV)"), relabel(keys.Back, "cancel"))
+		return s
+	}
+
+	if m.importPreview != nil {
+		p := m.importPreview
+		s := titleStyle.Render("Import Preview") + "\n\n"
+		source := p.path
+		if p.preset != "" {
+			source += fmt.Sprintf(" (%s preset)", p.preset)
+		}
+		s += normalStyle.Render(fmt.Sprintf("%s: will create %d contacts and update %d (%d unchanged, %d warnings)",
+			source, p.plan.Created(), p.plan.Merged(), p.plan.Unchanged, len(p.warnings)+len(p.plan.Warnings))) + "\n\n"
+		s += m.importView.View() + "\n\n"
+		s += helpView(m.windowWidth, relabel(keys.Confirm, "import"), relabel(keys.Deny, "cancel"), keys.Up, keys.Down)
+		return s
+	}
+
 	if m.confirmDelete && m.contactToDelete != nil {
 		s := titleStyle.Render("Delete Contact") + "\n\n"
 		s += normalStyle.Render(fmt.Sprintf("Are you sure you want to delete '%s'?", m.contactToDelete.Name)) + "\n\n"
 		s += errorStyle.Render("This action cannot be undone.") + "\n\n"
-		s += helpStyle.Render("y: confirm delete • n/esc: cancel")
+		s += helpView(m.windowWidth, relabel(keys.Confirm, "confirm delete"), keys.Deny, relabel(keys.Back, "cancel"))
 		return s
 	}
 
@@ -202,19 +590,37 @@ func (m ContactsListModel) View() string {
 	if m.err != nil {
 		s := titleStyle.Render("Contacts") + "\n\n"
 		s += errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n"
-		s += helpStyle.Render("esc: back to menu • q: quit")
+		s += helpView(m.windowWidth, relabel(keys.Refresh, "reload"), relabel(keys.Back, "back to menu"), keys.Quit)
 		return s
 	}
 
 	if len(m.contacts) == 0 {
 		s := titleStyle.Render("Contacts") + "\n\n"
-		s += normalStyle.Render("  No contacts found. Press 'n' to add a contact.") + "\n"
-		s += "\n" + helpStyle.Render("n: new contact • esc: back • q: quit")
+		s += normalStyle.Render(fmt.Sprintf("  No contacts found. Press '%s' to add a contact or '%s' to import a vCard or CSV.", keys.New.Help().Key, keys.Import.Help().Key)) + "\n"
+		if m.status != "" {
+			s += "\n" + statusStyle.Render("  "+m.status) + "\n"
+		}
+		s += "\n" + helpView(m.windowWidth, relabel(keys.New, "new contact"), relabel(keys.Import, "import vCard/CSV"), keys.Back, keys.Quit)
 		return s
 	}
 
 	s := m.list.View() + "\n"
-	s += helpStyle.Render("↑↓/jk: navigate • enter: edit • n: new • d: delete • /: search • r: refresh • esc: back • q: quit")
+	if m.status != "" {
+		s += statusStyle.Render(m.status) + "\n"
+	}
+	if m.duplicates > 0 {
+		s += statusStyle.Render(fmt.Sprintf("%d group(s) of possible duplicates - press %s to review", m.duplicates, keys.Duplicates.Help().Key)) + "\n"
+	}
+	s += helpView(m.windowWidth, keys.Help, relabel(keys.Open, "details"), keys.Edit, keys.New, keys.Delete, keys.Duplicates, keys.Groups, keys.Import, keys.Export, keys.ExportAll, keys.TagFilter, keys.Search, keys.Refresh, keys.Back, keys.Quit)
 
 	return s
 }
+
+func (m ContactsListModel) fullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{keys.Up, keys.Down, relabel(keys.Open, "details"), keys.Search, keys.TagFilter},
+		{keys.New, keys.Edit, keys.Delete, keys.Duplicates, keys.Groups},
+		{keys.Import, keys.Export, keys.ExportAll, keys.Refresh},
+		{keys.Back, keys.Quit, keys.Help},
+	}
+}
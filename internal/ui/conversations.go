@@ -1,15 +1,20 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
+	"github.com/saravenpi/chime/internal/config"
+	"github.com/saravenpi/chime/internal/contacts"
 	"github.com/saravenpi/chime/internal/imessage"
 	"github.com/saravenpi/chime/internal/models"
+	"github.com/saravenpi/chime/internal/resolver"
 )
 
 type chatItem struct {
@@ -19,12 +24,63 @@ type chatItem struct {
 
 type chatsFetchedMsg struct {
 	chats []models.Chat
+	known map[int64]bool
 	err   error
+	// blocklistErr is set when the chats were fetched without the blocklist or muted chats.
+	blocklistErr error
+}
+
+type conversationTab int
+
+const (
+	tabKnown conversationTab = iota
+	tabUnknown
+	tabAll
+	tabCount
+)
+
+var tabNames = [tabCount]string{"Known", "Unknown Senders", "All"}
+
+func tabFromConfig(name string) conversationTab {
+	switch name {
+	case "known":
+		return tabKnown
+	case "unknown":
+		return tabUnknown
+	}
+	return tabAll
+}
+
+// conversationFilters is the filter state of the conversations list. Screens opened from the
+// list carry it so that going back shows the list filtered the same way.
+type conversationFilters struct {
+	tab         conversationTab
+	unreadOnly  bool
+	groupFilter string
+	showBlocked bool
+}
+
+func defaultConversationFilters() conversationFilters {
+	return conversationFilters{tab: tabFromConfig(config.Current().DefaultTab)}
 }
 
 type autoRefreshMsg struct{}
 
+type chatUpdatedMsg struct {
+	status string
+	err    error
+}
+
+type conversationGroupsMsg struct {
+	groups  []string
+	handles map[string]map[string]bool
+	err     error
+}
+
 func (i chatItem) Title() string {
+	if i.chat.Blocked {
+		return "⊘ " + i.chat.DisplayName
+	}
 	if i.chat.HasUnread {
 		return "● " + i.chat.DisplayName
 	}
@@ -37,6 +93,9 @@ func (i chatItem) Description() string {
 	if len(preview) > 50 {
 		preview = preview[:47] + "..."
 	}
+	if i.chat.Muted {
+		timeAgo += " • muted"
+	}
 	if i.chat.HasUnread && i.chat.UnreadCount > 0 {
 		return fmt.Sprintf("%s • %d unread • %s", timeAgo, i.chat.UnreadCount, preview)
 	}
@@ -76,7 +135,7 @@ func formatTimeAgo(t time.Time) string {
 	if duration < 7*24*time.Hour {
 		return fmt.Sprintf("%dd ago", int(duration.Hours()/24))
 	}
-	return t.Format("Jan 2")
+	return t.Format(config.Current().ShortDateLayout())
 }
 
 type ConversationsModel struct {
@@ -90,6 +149,16 @@ type ConversationsModel struct {
 	windowHeight   int
 	selectedIndex  int
 	showUnreadOnly bool
+	groups         []string
+	groupHandles   map[string]map[string]bool
+	groupFilter    string
+	showBlocked    bool
+	status         string
+	actionErr      error
+	blocklistErr   error
+	tab            conversationTab
+	known          map[int64]bool
+	showHelp       bool
 }
 
 func NewConversationsModel() ConversationsModel {
@@ -99,63 +168,262 @@ func NewConversationsModel() ConversationsModel {
 
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
-		Foreground(lipgloss.Color("5")).
+		Foreground(accentColor).
 		Bold(true)
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
-		Foreground(lipgloss.Color("8"))
+		Foreground(dimColor)
 
 	l := list.New([]list.Item{}, delegate, 80, 20)
 	l.Title = "Conversations"
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
 	l.SetShowHelp(false)
+	l.KeyMap = listKeyMap()
 
 	return ConversationsModel{
 		list:         l,
 		loading:      true,
+		tab:          tabFromConfig(config.Current().DefaultTab),
 		spinner:      s,
 		windowWidth:  80,
 		windowHeight: 30,
 	}
 }
 
+// newFilteredConversationsModel creates the conversations list with filters already applied.
+func newFilteredConversationsModel(filters conversationFilters) ConversationsModel {
+	m := NewConversationsModel()
+	m.tab = filters.tab
+	m.showUnreadOnly = filters.unreadOnly
+	m.groupFilter = filters.groupFilter
+	m.showBlocked = filters.showBlocked
+	return m
+}
+
+func (m ConversationsModel) filters() conversationFilters {
+	return conversationFilters{
+		tab:         m.tab,
+		unreadOnly:  m.showUnreadOnly,
+		groupFilter: m.groupFilter,
+		showBlocked: m.showBlocked,
+	}
+}
+
 func (m ConversationsModel) Init() tea.Cmd {
-	return tea.Batch(m.spinner.Tick, m.fetchChatsCmd(), m.autoRefreshCmd())
+	return tea.Batch(m.spinner.Tick, m.fetchChatsCmd(), m.loadGroupsCmd(), m.autoRefreshCmd())
+}
+
+// loadGroupsCmd loads each contact group with the normalised handles of its members,
+// for filtering conversations by group.
+func (m ConversationsModel) loadGroupsCmd() tea.Cmd {
+	return func() tea.Msg {
+		groups, err := contacts.ListGroups()
+		if err != nil {
+			return conversationGroupsMsg{err: err}
+		}
+
+		msg := conversationGroupsMsg{handles: make(map[string]map[string]bool)}
+		for _, group := range groups {
+			members, err := contacts.GroupMembers(group)
+			if err != nil {
+				return conversationGroupsMsg{err: err}
+			}
+			handles := make(map[string]bool)
+			for _, member := range members {
+				for _, identifier := range append(append([]string{}, member.PhoneNumbers...), member.Emails...) {
+					handles[contacts.NormalizeIdentifier(identifier)] = true
+				}
+			}
+			msg.groups = append(msg.groups, group.Name)
+			msg.handles[group.Name] = handles
+		}
+		return msg
+	}
 }
 
 func (m ConversationsModel) autoRefreshCmd() tea.Cmd {
-	return tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
+	return tea.Tick(config.Current().RefreshInterval, func(t time.Time) tea.Msg {
 		return autoRefreshMsg{}
 	})
 }
 
 func (m ConversationsModel) fetchChatsCmd() tea.Cmd {
+	showBlocked := m.showBlocked
 	return func() tea.Msg {
-		chats, err := imessage.GetChats()
-		return chatsFetchedMsg{chats: chats, err: err}
+		fetch := imessage.GetChats
+		if showBlocked {
+			fetch = imessage.GetChatsIncludingBlocked
+		}
+		chats, err := fetch()
+		var blocklistErr *imessage.BlocklistError
+		if err != nil && !errors.As(err, &blocklistErr) {
+			return chatsFetchedMsg{err: err}
+		}
+
+		known := make(map[int64]bool, len(chats))
+		for _, chat := range chats {
+			known[chat.ROWID] = isKnownChat(chat)
+		}
+		return chatsFetchedMsg{chats: chats, known: known, blocklistErr: err}
+	}
+}
+
+// isKnownChat reports whether any participant of chat resolves to a contact name.
+func isKnownChat(chat models.Chat) bool {
+	for _, participant := range chat.Participants {
+		if resolver.Lookup(participant) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// inTab reports whether chat belongs on tab.
+func (m ConversationsModel) inTab(chat models.Chat, tab conversationTab) bool {
+	switch tab {
+	case tabKnown:
+		return m.known[chat.ROWID]
+	case tabUnknown:
+		return !m.known[chat.ROWID]
+	}
+	return true
+}
+
+// tabUnread returns the number of unread messages in the chats on tab.
+func (m ConversationsModel) tabUnread(tab conversationTab) int {
+	unread := 0
+	for _, chat := range m.allChats {
+		if chat.HasUnread && m.inTab(chat, tab) {
+			unread += chat.UnreadCount
+		}
+	}
+	return unread
+}
+
+func (m ConversationsModel) renderTabs() string {
+	tabs := make([]string, 0, tabCount)
+	for tab := conversationTab(0); tab < tabCount; tab++ {
+		label := tabNames[tab]
+		if unread := m.tabUnread(tab); unread > 0 {
+			label += fmt.Sprintf(" (%d)", unread)
+		}
+		if tab == m.tab {
+			tabs = append(tabs, selectedStyle.Render("["+label+"]"))
+		} else {
+			tabs = append(tabs, helpStyle.Render(" "+label+" "))
+		}
+	}
+	return strings.Join(tabs, "  ")
+}
+
+// toggleMuteCmd mutes the chat, or unmutes it if it is muted.
+func (m ConversationsModel) toggleMuteCmd(chat models.Chat) tea.Cmd {
+	return func() tea.Msg {
+		if err := contacts.SetChatMuted(chat.ChatID, !chat.Muted); err != nil {
+			return chatUpdatedMsg{err: err}
+		}
+		if chat.Muted {
+			return chatUpdatedMsg{status: "Unmuted " + chat.DisplayName}
+		}
+		return chatUpdatedMsg{status: "Muted " + chat.DisplayName}
+	}
+}
+
+// toggleBlockCmd blocks the chat, or unblocks it by removing every blocklist entry that
+// matches it or, for a group, its participants.
+func (m ConversationsModel) toggleBlockCmd(chat models.Chat) tea.Cmd {
+	return func() tea.Msg {
+		if !chat.Blocked {
+			if err := contacts.Block(chat.ChatID); err != nil {
+				return chatUpdatedMsg{err: err}
+			}
+			return chatUpdatedMsg{status: fmt.Sprintf("Blocked %s • B: show blocked", chat.DisplayName)}
+		}
+
+		var removed []string
+		for _, handle := range append([]string{chat.ChatID}, chat.Participants...) {
+			entries, err := contacts.Unblock(handle)
+			if err != nil {
+				return chatUpdatedMsg{err: err}
+			}
+			removed = append(removed, entries...)
+		}
+		return chatUpdatedMsg{status: fmt.Sprintf("Unblocked %s (removed %s)", chat.DisplayName, strings.Join(removed, ", "))}
 	}
 }
 
 func (m ConversationsModel) filterChats() []models.Chat {
-	if !m.showUnreadOnly {
+	if !m.showUnreadOnly && m.groupFilter == "" && !m.showBlocked && m.tab == tabAll {
 		return m.allChats
 	}
 
 	filtered := []models.Chat{}
 	for _, chat := range m.allChats {
-		if chat.HasUnread {
-			filtered = append(filtered, chat)
+		if m.showBlocked && !chat.Blocked {
+			continue
+		}
+		if !m.showBlocked && !m.inTab(chat, m.tab) {
+			continue
 		}
+		if m.showUnreadOnly && !chat.HasUnread {
+			continue
+		}
+		if m.groupFilter != "" && !m.inGroup(chat) {
+			continue
+		}
+		filtered = append(filtered, chat)
 	}
 	return filtered
 }
 
+// inGroup reports whether any participant of chat is a member of the filtered group.
+func (m ConversationsModel) inGroup(chat models.Chat) bool {
+	handles := m.groupHandles[m.groupFilter]
+	for _, participant := range chat.Participants {
+		if handles[contacts.NormalizeIdentifier(participant)] {
+			return true
+		}
+	}
+	return false
+}
+
+// cycleGroupFilter moves the group filter to the next group, wrapping back to all chats.
+func (m *ConversationsModel) cycleGroupFilter() {
+	next := ""
+	if m.groupFilter == "" && len(m.groups) > 0 {
+		next = m.groups[0]
+	} else {
+		for i, group := range m.groups {
+			if group == m.groupFilter && i+1 < len(m.groups) {
+				next = m.groups[i+1]
+			}
+		}
+	}
+	m.groupFilter = next
+	m.applyFilter()
+}
+
+func (m *ConversationsModel) applyFilter() {
+	m.chats = m.filterChats()
+	items := make([]list.Item, len(m.chats))
+	for i, chat := range m.chats {
+		items[i] = chatItem{chat: chat, index: i}
+	}
+	m.list.SetItems(items)
+	m.updateTitle()
+}
+
 func (m *ConversationsModel) updateTitle() {
-	if m.showUnreadOnly {
+	if m.showBlocked {
+		m.list.Title = fmt.Sprintf("Blocked Conversations - %d chats", len(m.chats))
+	} else if m.showUnreadOnly {
 		m.list.Title = fmt.Sprintf("Conversations - %d unread of %d total", len(m.chats), len(m.allChats))
 	} else {
 		m.list.Title = fmt.Sprintf("Conversations - %d chats", len(m.chats))
 	}
+	if m.groupFilter != "" {
+		m.list.Title += " in @" + m.groupFilter
+	}
 }
 
 func (m ConversationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -164,7 +432,7 @@ func (m ConversationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.windowWidth = msg.Width
 		m.windowHeight = msg.Height
 		m.list.SetWidth(msg.Width)
-		m.list.SetHeight(msg.Height - 4)
+		m.list.SetHeight(msg.Height - 5)
 		return m, nil
 
 	case chatsFetchedMsg:
@@ -175,15 +443,31 @@ func (m ConversationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		m.allChats = msg.chats
-		m.chats = m.filterChats()
-		items := make([]list.Item, len(m.chats))
-		for i, chat := range m.chats {
-			items[i] = chatItem{chat: chat, index: i}
+		m.known = msg.known
+		m.blocklistErr = msg.blocklistErr
+		m.applyFilter()
+		return m, nil
+
+	case conversationGroupsMsg:
+		if msg.err != nil {
+			return m, nil
 		}
-		m.list.SetItems(items)
-		m.updateTitle()
+		m.groups = msg.groups
+		m.groupHandles = msg.handles
+		if _, ok := m.groupHandles[m.groupFilter]; !ok {
+			m.groupFilter = ""
+		}
+		m.applyFilter()
 		return m, nil
 
+	case ContactsChangedMsg:
+		return m, tea.Batch(m.fetchChatsCmd(), m.loadGroupsCmd())
+
+	case chatUpdatedMsg:
+		m.status = msg.status
+		m.actionErr = msg.err
+		return m, m.fetchChatsCmd()
+
 	case autoRefreshMsg:
 		if !m.loading {
 			return m, tea.Batch(m.fetchChatsCmd(), m.autoRefreshCmd())
@@ -199,16 +483,30 @@ func (m ConversationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		if msg.String() == "q" || msg.String() == "ctrl+c" {
+		if key.Matches(msg, keys.ForceQuit) {
 			return m, tea.Quit
 		}
 
-		if msg.String() == "esc" {
-			if m.list.FilterState() == list.Filtering {
-				var cmd tea.Cmd
-				m.list, cmd = m.list.Update(msg)
-				return m, cmd
-			}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		if m.list.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, keys.Back):
 			menuModel := NewMenuModel()
 			if m.windowWidth > 0 {
 				updatedModel, _ := menuModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
@@ -217,36 +515,58 @@ func (m ConversationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return menuModel, menuModel.Init()
 		}
 
-		if m.list.FilterState() != list.Filtering {
-			if msg.String() == "r" && !m.loading {
+		if !m.loading {
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				m.loading = true
 				return m, tea.Batch(m.spinner.Tick, m.fetchChatsCmd())
-			}
 
-			if msg.String() == "u" && !m.loading {
+			case key.Matches(msg, keys.UnreadFilter):
 				m.showUnreadOnly = !m.showUnreadOnly
-				m.chats = m.filterChats()
-				items := make([]list.Item, len(m.chats))
-				for i, chat := range m.chats {
-					items[i] = chatItem{chat: chat, index: i}
+				m.applyFilter()
+				return m, nil
+
+			case key.Matches(msg, keys.NextGroup):
+				m.cycleGroupFilter()
+				return m, nil
+
+			case key.Matches(msg, keys.NextTab, keys.PrevTab) && !m.showBlocked:
+				if key.Matches(msg, keys.NextTab) {
+					m.tab = (m.tab + 1) % tabCount
+				} else {
+					m.tab = (m.tab + tabCount - 1) % tabCount
 				}
-				m.list.SetItems(items)
-				m.updateTitle()
+				m.applyFilter()
 				return m, nil
-			}
 
-			if msg.String() == "n" && !m.loading {
-				newConvModel := NewNewConversationModel(m.showUnreadOnly)
+			case key.Matches(msg, keys.Mute):
+				if item, ok := m.list.SelectedItem().(chatItem); ok {
+					return m, m.toggleMuteCmd(item.chat)
+				}
+
+			case key.Matches(msg, keys.Block):
+				if item, ok := m.list.SelectedItem().(chatItem); ok {
+					return m, m.toggleBlockCmd(item.chat)
+				}
+
+			case key.Matches(msg, keys.ShowBlocked):
+				m.showBlocked = !m.showBlocked
+				m.status = ""
+				m.actionErr = nil
+				m.loading = true
+				return m, tea.Batch(m.spinner.Tick, m.fetchChatsCmd())
+
+			case key.Matches(msg, keys.New):
+				newConvModel := NewNewConversationModel(m.filters())
 				if m.windowWidth > 0 {
 					updatedModel, _ := newConvModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 					newConvModel = updatedModel.(NewConversationModel)
 				}
 				return newConvModel, newConvModel.Init()
-			}
 
-			if msg.String() == "enter" && len(m.chats) > 0 && !m.loading {
+			case key.Matches(msg, keys.Open) && len(m.chats) > 0:
 				if item, ok := m.list.SelectedItem().(chatItem); ok {
-					messagesModel := NewMessagesModel(item.chat, m.showUnreadOnly)
+					messagesModel := NewMessagesModel(item.chat, m.filters())
 					if m.windowWidth > 0 {
 						updatedModel, _ := messagesModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 						messagesModel = updatedModel.(MessagesModel)
@@ -265,6 +585,10 @@ func (m ConversationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m ConversationsModel) View() string {
+	if m.showHelp {
+		return helpOverlay("Conversations", m.windowWidth, m.fullHelp()...)
+	}
+
 	if m.loading {
 		return fmt.Sprintf("\n  %s Loading conversations...\n", m.spinner.View())
 	}
@@ -273,23 +597,87 @@ func (m ConversationsModel) View() string {
 		s := titleStyle.Render("Conversations") + "\n\n"
 		s += errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n"
 		s += helpStyle.Render("Make sure you have access to Messages.app database") + "\n"
-		s += helpStyle.Render("q: quit")
+		s += helpView(m.windowWidth, keys.Quit)
 		return s
 	}
 
 	if len(m.chats) == 0 {
 		s := titleStyle.Render("Conversations") + "\n\n"
-		s += normalStyle.Render("  No conversations found.") + "\n"
-		s += "\n" + helpStyle.Render("r: refresh • q: quit")
+		if m.status != "" {
+			s += statusStyle.Render(m.status) + "\n\n"
+		}
+		if m.actionErr != nil {
+			s += errorStyle.Render(fmt.Sprintf("Error: %v", m.actionErr)) + "\n\n"
+		}
+		if m.blocklistErr != nil {
+			s += errorStyle.Render(fmt.Sprintf("Warning: %v (showing chats without it)", m.blocklistErr)) + "\n\n"
+		}
+		if m.showBlocked {
+			s += normalStyle.Render("  No blocked conversations.") + "\n"
+			s += "\n" + helpView(m.windowWidth, relabel(keys.ShowBlocked, "back to conversations"), keys.Refresh, keys.Quit)
+			return s
+		}
+		s += m.renderTabs() + "\n\n"
+		if m.groupFilter != "" {
+			s += normalStyle.Render(fmt.Sprintf("  No conversations with @%s.", m.groupFilter)) + "\n"
+			s += "\n" + helpView(m.windowWidth, keys.NextGroup, relabel(keys.NextTab, "switch tab"), keys.Refresh, keys.Quit)
+			return s
+		}
+		switch m.tab {
+		case tabKnown:
+			s += normalStyle.Render("  No conversations with your contacts.") + "\n"
+		case tabUnknown:
+			s += normalStyle.Render("  No conversations from unknown senders.") + "\n"
+		default:
+			s += normalStyle.Render("  No conversations found.") + "\n"
+		}
+		s += "\n" + helpView(m.windowWidth, relabel(keys.NextTab, "switch tab"), keys.New, keys.Refresh, keys.Quit)
 		return s
 	}
 
-	s := m.list.View() + "\n"
-	filterStatus := "all"
-	if m.showUnreadOnly {
-		filterStatus = "unread only"
+	s := ""
+	if !m.showBlocked {
+		s += m.renderTabs() + "\n"
+	}
+	s += m.list.View() + "\n"
+	if m.status != "" {
+		s += statusStyle.Render(m.status) + "\n"
+	}
+	if m.actionErr != nil {
+		s += errorStyle.Render(fmt.Sprintf("Error: %v", m.actionErr)) + "\n"
 	}
-	s += helpStyle.Render(fmt.Sprintf("↑↓/jk: navigate • enter: open • n: new • u: toggle filter (%s) • /: search • r: refresh • esc: back • q: quit", filterStatus))
+	if m.blocklistErr != nil {
+		s += errorStyle.Render(fmt.Sprintf("Warning: %v (showing chats without it)", m.blocklistErr)) + "\n"
+	}
+	if m.showBlocked {
+		s += helpView(m.windowWidth, keys.Help, keys.Open, relabel(keys.Block, "unblock"), relabel(keys.ShowBlocked, "back to conversations"), keys.Search, keys.Refresh, keys.Quit)
+		return s
+	}
+	s += helpView(m.windowWidth, keys.Help, keys.Open, relabel(keys.NextTab, "switch tab"), keys.New, m.unreadHelp(), m.groupHelp(), keys.Mute, keys.Block, keys.ShowBlocked, keys.Search, keys.Refresh, keys.Back, keys.Quit)
 
 	return s
 }
+
+// unreadHelp and groupHelp describe the unread and group filter keys with the filter in use.
+func (m ConversationsModel) unreadHelp() key.Binding {
+	if m.showUnreadOnly {
+		return relabel(keys.UnreadFilter, "filter (unread only)")
+	}
+	return relabel(keys.UnreadFilter, "filter (all)")
+}
+
+func (m ConversationsModel) groupHelp() key.Binding {
+	if m.groupFilter != "" {
+		return relabel(keys.NextGroup, fmt.Sprintf("group (@%s)", m.groupFilter))
+	}
+	return relabel(keys.NextGroup, "group (all)")
+}
+
+func (m ConversationsModel) fullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{keys.Up, keys.Down, keys.Open, keys.Search},
+		{keys.NextTab, keys.PrevTab, m.unreadHelp(), m.groupHelp()},
+		{keys.New, keys.Mute, keys.Block, keys.ShowBlocked},
+		{keys.Refresh, keys.Back, keys.Quit, keys.Help},
+	}
+}
@@ -0,0 +1,326 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/saravenpi/chime/internal/contacts"
+)
+
+type duplicateItem struct {
+	group contacts.DuplicateGroup
+}
+
+func (i duplicateItem) FilterValue() string { return i.Title() }
+func (i duplicateItem) Title() string {
+	names := make([]string, len(i.group.Contacts))
+	for j, contact := range i.group.Contacts {
+		names[j] = contact.Name
+	}
+	return strings.Join(names, " / ")
+}
+func (i duplicateItem) Description() string { return strings.Join(i.group.Reasons, " • ") }
+
+type duplicatesLoadedMsg struct {
+	groups []contacts.DuplicateGroup
+	err    error
+}
+
+type contactsMergedMsg struct {
+	merged  contacts.Contact
+	removed int
+	err     error
+}
+
+type DuplicatesModel struct {
+	list         list.Model
+	groups       []contacts.DuplicateGroup
+	loading      bool
+	err          error
+	status       string
+	merging      bool
+	saving       bool
+	group        contacts.DuplicateGroup
+	primary      int
+	windowWidth  int
+	windowHeight int
+	showHelp     bool
+}
+
+// NewDuplicatesModel creates a view listing groups of contacts that look like the same person.
+func NewDuplicatesModel() DuplicatesModel {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(accentColor).
+		Bold(true)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(dimColor)
+
+	l := list.New([]list.Item{}, delegate, 80, 20)
+	l.Title = "Possible Duplicates"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.KeyMap = listKeyMap()
+
+	return DuplicatesModel{
+		list:         l,
+		loading:      true,
+		windowWidth:  80,
+		windowHeight: 30,
+	}
+}
+
+func (m DuplicatesModel) Init() tea.Cmd {
+	return m.loadDuplicatesCmd()
+}
+
+func (m DuplicatesModel) loadDuplicatesCmd() tea.Cmd {
+	return func() tea.Msg {
+		all, err := contacts.ListContacts()
+		if err != nil {
+			return duplicatesLoadedMsg{err: err}
+		}
+		return duplicatesLoadedMsg{groups: contacts.FindDuplicates(all)}
+	}
+}
+
+func (m DuplicatesModel) mergeCmd() tea.Cmd {
+	primary := m.group.Contacts[m.primary]
+	var others []contacts.Contact
+	for i, contact := range m.group.Contacts {
+		if i != m.primary {
+			others = append(others, contact)
+		}
+	}
+
+	return func() tea.Msg {
+		merged, err := contacts.MergeAndSave(primary, others...)
+		return contactsMergedMsg{merged: merged, removed: len(others), err: err}
+	}
+}
+
+func (m DuplicatesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 4)
+		return m, nil
+
+	case duplicatesLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+
+		m.err = nil
+		m.groups = msg.groups
+		items := make([]list.Item, len(m.groups))
+		for i, group := range m.groups {
+			items[i] = duplicateItem{group: group}
+		}
+		m.list.SetItems(items)
+		m.list.Title = fmt.Sprintf("Possible Duplicates - %d groups", len(m.groups))
+		return m, nil
+
+	case contactsMergedMsg:
+		m.merging = false
+		m.saving = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Merged %d contact(s) into %s", msg.removed, msg.merged.Name)
+		m.loading = true
+		return m, m.loadDuplicatesCmd()
+
+	case ContactsChangedMsg:
+		if m.merging {
+			return m, nil
+		}
+		return m, m.loadDuplicatesCmd()
+
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.ForceQuit) {
+			return m, tea.Quit
+		}
+
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		if m.saving {
+			return m, nil
+		}
+
+		if m.merging {
+			switch {
+			case key.Matches(msg, keys.Back):
+				m.merging = false
+			case key.Matches(msg, keys.NextField, keys.Right, keys.Down):
+				m.primary = (m.primary + 1) % len(m.group.Contacts)
+			case key.Matches(msg, keys.PrevField, keys.Left, keys.Up):
+				m.primary = (m.primary - 1 + len(m.group.Contacts)) % len(m.group.Contacts)
+			case key.Matches(msg, keys.Submit, keys.Confirm):
+				m.saving = true
+				return m, m.mergeCmd()
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, keys.Back, keys.Quit):
+			contactsModel := NewContactsListModel()
+			if m.windowWidth > 0 {
+				updatedModel, _ := contactsModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+				contactsModel = updatedModel.(ContactsListModel)
+			}
+			return contactsModel, contactsModel.Init()
+
+		case key.Matches(msg, keys.Refresh):
+			m.loading = true
+			m.status = ""
+			return m, m.loadDuplicatesCmd()
+
+		case key.Matches(msg, keys.Open):
+			if item, ok := m.list.SelectedItem().(duplicateItem); ok {
+				m.merging = true
+				m.group = item.group
+				m.primary = 0
+				m.status = ""
+				m.err = nil
+			}
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m DuplicatesModel) View() string {
+	if m.showHelp {
+		return helpOverlay("Possible Duplicates", m.windowWidth,
+			[]key.Binding{keys.Up, keys.Down, relabel(keys.Open, "review and merge"), keys.Refresh},
+			[]key.Binding{relabel(keys.NextField, "keep next"), relabel(keys.PrevField, "keep previous"), relabel(keys.Confirm, "merge")},
+			[]key.Binding{keys.Back, keys.Help},
+		)
+	}
+
+	if m.merging {
+		return m.mergeView()
+	}
+
+	if m.loading {
+		return "\n  Looking for duplicates...\n"
+	}
+
+	if m.err != nil {
+		s := titleStyle.Render("Possible Duplicates") + "\n\n"
+		s += errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n"
+		s += helpView(m.windowWidth, relabel(keys.Refresh, "retry"), keys.Back)
+		return s
+	}
+
+	if len(m.groups) == 0 {
+		s := titleStyle.Render("Possible Duplicates") + "\n\n"
+		if m.status != "" {
+			s += statusStyle.Render("  "+m.status) + "\n\n"
+		}
+		s += normalStyle.Render("  No duplicate contacts found.") + "\n\n"
+		s += helpView(m.windowWidth, keys.Refresh, keys.Back)
+		return s
+	}
+
+	s := m.list.View() + "\n"
+	if m.status != "" {
+		s += statusStyle.Render(m.status) + "\n"
+	}
+	s += helpView(m.windowWidth, keys.Help, relabel(keys.Open, "review and merge"), keys.Refresh, keys.Back)
+	return s
+}
+
+func (m DuplicatesModel) mergeView() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Merge Contacts") + "\n\n")
+	b.WriteString(helpStyle.Render(strings.Join(m.group.Reasons, " • ")) + "\n\n")
+
+	var others []contacts.Contact
+	for i, contact := range m.group.Contacts {
+		marker := "  "
+		style := normalStyle
+		if i == m.primary {
+			marker = "● "
+			style = selectedStyle
+		} else {
+			others = append(others, contact)
+		}
+		b.WriteString(style.Render(marker+contact.Name) + "\n")
+		b.WriteString(renderContactDetails(contact, "    "))
+	}
+
+	merged := contacts.MergeContacts(m.group.Contacts[m.primary], others...)
+	b.WriteString("\n" + normalStyle.Render("Result:") + "\n")
+	b.WriteString(selectedStyle.Render("  "+merged.Name) + "\n")
+	b.WriteString(renderContactDetails(merged, "    "))
+
+	if m.saving {
+		b.WriteString("\n" + statusStyle.Render("Merging...") + "\n")
+		return b.String()
+	}
+	b.WriteString("\n" + helpView(m.windowWidth, relabel(keys.NextField, "choose which contact to keep"), relabel(keys.Submit, "merge and delete the others"), relabel(keys.Back, "cancel")))
+	return b.String()
+}
+
+// renderContactDetails lists a contact's identifiers and optional fields, one per line.
+func renderContactDetails(contact contacts.Contact, indent string) string {
+	var lines []string
+	for _, phone := range contact.PhoneNumbers {
+		lines = append(lines, withLabel(phone, contact.Label(phone)))
+	}
+	for _, email := range contact.Emails {
+		lines = append(lines, withLabel(email, contact.Label(email)))
+	}
+	if contact.Nickname != "" {
+		lines = append(lines, "Nickname: "+contact.Nickname)
+	}
+	if contact.Organization != "" {
+		lines = append(lines, "Organisation: "+contact.Organization)
+	}
+	if contact.Birthday != "" {
+		lines = append(lines, "Birthday: "+contact.Birthday)
+	}
+	if len(contact.Tags) > 0 {
+		lines = append(lines, "#"+strings.Join(contact.Tags, " #"))
+	}
+	if contact.Notes != "" {
+		lines = append(lines, "Notes: "+strings.ReplaceAll(contact.Notes, "\n", " "))
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(helpStyle.Render(indent+line) + "\n")
+	}
+	return b.String()
+}
+
+func withLabel(identifier, label string) string {
+	if label == "" {
+		return identifier
+	}
+	return fmt.Sprintf("%s (%s)", identifier, label)
+}
@@ -0,0 +1,481 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/saravenpi/chime/internal/contacts"
+)
+
+const maxGroupPreviewNames = 3
+
+type groupItem struct {
+	group   contacts.Group
+	members []contacts.Contact
+}
+
+func (i groupItem) FilterValue() string { return i.group.Name }
+func (i groupItem) Title() string       { return "@" + i.group.Name }
+func (i groupItem) Description() string {
+	if len(i.members) == 0 {
+		return "no members"
+	}
+	names := make([]string, 0, maxGroupPreviewNames)
+	for _, member := range i.members {
+		if len(names) == maxGroupPreviewNames {
+			names = append(names, "…")
+			break
+		}
+		names = append(names, member.DisplayName())
+	}
+	return fmt.Sprintf("%d members • %s", len(i.members), strings.Join(names, ", "))
+}
+
+type groupMemberItem struct {
+	contact contacts.Contact
+	member  bool
+}
+
+func (i groupMemberItem) FilterValue() string { return i.contact.Name + " " + i.contact.Nickname }
+func (i groupMemberItem) Title() string {
+	if i.member {
+		return "☑ " + i.contact.Name
+	}
+	return "☐ " + i.contact.Name
+}
+func (i groupMemberItem) Description() string { return contactItem{contact: i.contact}.Description() }
+
+type groupsLoadedMsg struct {
+	groups   []contacts.Group
+	contacts []contacts.Contact
+	err      error
+}
+
+type groupsUpdatedMsg struct {
+	status string
+	open   string
+	err    error
+}
+
+type groupPromptMode int
+
+const (
+	groupPromptNone groupPromptMode = iota
+	groupPromptCreate
+	groupPromptRename
+)
+
+type GroupsModel struct {
+	list          list.Model
+	members       list.Model
+	groups        []contacts.Group
+	contacts      []contacts.Contact
+	editing       string
+	prompt        groupPromptMode
+	nameInput     textinput.Model
+	confirmDelete bool
+	loading       bool
+	err           error
+	status        string
+	windowWidth   int
+	windowHeight  int
+	showHelp      bool
+}
+
+// NewGroupsModel creates a view for creating groups and choosing their members.
+func NewGroupsModel() GroupsModel {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(accentColor).
+		Bold(true)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(dimColor)
+
+	l := list.New([]list.Item{}, delegate, 80, 20)
+	l.Title = "Groups"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.KeyMap = listKeyMap()
+
+	members := list.New([]list.Item{}, delegate, 80, 20)
+	members.SetShowStatusBar(false)
+	members.SetFilteringEnabled(true)
+	members.SetShowHelp(false)
+	members.KeyMap = listKeyMap()
+
+	nameInput := textinput.New()
+	nameInput.Placeholder = "Group name, e.g. oncall"
+	nameInput.CharLimit = 50
+	nameInput.Width = 40
+
+	return GroupsModel{
+		list:         l,
+		members:      members,
+		nameInput:    nameInput,
+		loading:      true,
+		windowWidth:  80,
+		windowHeight: 30,
+	}
+}
+
+func (m GroupsModel) Init() tea.Cmd {
+	return m.loadGroupsCmd()
+}
+
+func (m GroupsModel) loadGroupsCmd() tea.Cmd {
+	return func() tea.Msg {
+		groups, err := contacts.ListGroups()
+		if err != nil {
+			return groupsLoadedMsg{err: err}
+		}
+		all, err := contacts.ListContacts()
+		if err != nil {
+			return groupsLoadedMsg{err: err}
+		}
+		return groupsLoadedMsg{groups: groups, contacts: all}
+	}
+}
+
+func (m GroupsModel) selectedGroup() (contacts.Group, bool) {
+	if item, ok := m.list.SelectedItem().(groupItem); ok {
+		return item.group, true
+	}
+	return contacts.Group{}, false
+}
+
+func (m GroupsModel) findGroup(name string) contacts.Group {
+	for _, group := range m.groups {
+		if strings.EqualFold(group.Name, name) {
+			return group
+		}
+	}
+	return contacts.Group{Name: name}
+}
+
+func (m *GroupsModel) setGroupItems() {
+	items := make([]list.Item, len(m.groups))
+	for i, group := range m.groups {
+		var members []contacts.Contact
+		for _, contact := range m.contacts {
+			if group.HasMember(contact.ID) {
+				members = append(members, contact)
+			}
+		}
+		items[i] = groupItem{group: group, members: members}
+	}
+	m.list.SetItems(items)
+	m.list.Title = fmt.Sprintf("Groups - %d", len(m.groups))
+}
+
+func (m *GroupsModel) setMemberItems() {
+	group := m.findGroup(m.editing)
+	items := make([]list.Item, len(m.contacts))
+	count := 0
+	for i, contact := range m.contacts {
+		member := group.HasMember(contact.ID)
+		if member {
+			count++
+		}
+		items[i] = groupMemberItem{contact: contact, member: member}
+	}
+	m.members.SetItems(items)
+	m.members.Title = fmt.Sprintf("@%s - %d members", group.Name, count)
+}
+
+func (m GroupsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 4)
+		m.members.SetWidth(msg.Width)
+		m.members.SetHeight(msg.Height - 4)
+		return m, nil
+
+	case groupsLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.groups = msg.groups
+		m.contacts = msg.contacts
+		m.setGroupItems()
+		if m.editing != "" {
+			m.setMemberItems()
+		}
+		return m, nil
+
+	case groupsUpdatedMsg:
+		m.err = msg.err
+		if msg.status != "" {
+			m.status = msg.status
+		}
+		if msg.open != "" && msg.err == nil {
+			m.editing = msg.open
+			m.members.ResetFilter()
+		}
+		return m, m.loadGroupsCmd()
+
+	case ContactsChangedMsg:
+		return m, m.loadGroupsCmd()
+
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.ForceQuit) {
+			return m, tea.Quit
+		}
+
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		if m.prompt != groupPromptNone {
+			return m.updatePrompt(msg)
+		}
+
+		if m.confirmDelete {
+			switch {
+			case key.Matches(msg, keys.Confirm):
+				m.confirmDelete = false
+				if group, ok := m.selectedGroup(); ok {
+					return m, groupCmd(fmt.Sprintf("Deleted @%s", group.Name), func() error {
+						return contacts.DeleteGroup(group.Name)
+					})
+				}
+			case key.Matches(msg, keys.Deny, keys.Back):
+				m.confirmDelete = false
+			}
+			return m, nil
+		}
+
+		if m.editing != "" {
+			return m.updateMembers(msg)
+		}
+
+		switch {
+		case key.Matches(msg, keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, keys.Back, keys.Quit):
+			contactsModel := NewContactsListModel()
+			if m.windowWidth > 0 {
+				updatedModel, _ := contactsModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+				contactsModel = updatedModel.(ContactsListModel)
+			}
+			return contactsModel, contactsModel.Init()
+
+		case key.Matches(msg, keys.New):
+			return m, m.openPrompt(groupPromptCreate, "")
+
+		case key.Matches(msg, keys.Edit):
+			if group, ok := m.selectedGroup(); ok {
+				return m, m.openPrompt(groupPromptRename, group.Name)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Delete):
+			if _, ok := m.selectedGroup(); ok {
+				m.confirmDelete = true
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Refresh):
+			m.loading = true
+			m.status = ""
+			m.err = nil
+			return m, m.loadGroupsCmd()
+
+		case key.Matches(msg, keys.Open):
+			if group, ok := m.selectedGroup(); ok {
+				m.editing = group.Name
+				m.status = ""
+				m.err = nil
+				m.members.ResetFilter()
+				m.setMemberItems()
+			}
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m GroupsModel) updateMembers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.members.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.members, cmd = m.members.Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(msg, keys.Help):
+		m.showHelp = true
+		return m, nil
+
+	case key.Matches(msg, keys.Back):
+		if m.members.FilterState() == list.FilterApplied {
+			m.members.ResetFilter()
+			return m, nil
+		}
+		m.editing = ""
+		m.status = ""
+		return m, nil
+
+	case key.Matches(msg, keys.Select, keys.Open):
+		item, ok := m.members.SelectedItem().(groupMemberItem)
+		if !ok {
+			return m, nil
+		}
+		item.member = !item.member
+		m.members.SetItem(m.members.GlobalIndex(), item)
+
+		name, id, member := m.editing, item.contact.ID, item.member
+		return m, groupCmd("", func() error {
+			return contacts.SetGroupMember(name, id, member)
+		})
+	}
+
+	var cmd tea.Cmd
+	m.members, cmd = m.members.Update(msg)
+	return m, cmd
+}
+
+func (m *GroupsModel) openPrompt(mode groupPromptMode, value string) tea.Cmd {
+	m.prompt = mode
+	m.err = nil
+	m.status = ""
+	m.nameInput.SetValue(value)
+	m.nameInput.CursorEnd()
+	return m.nameInput.Focus()
+}
+
+func (m GroupsModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back):
+		m.prompt = groupPromptNone
+		m.nameInput.Blur()
+		return m, nil
+
+	case key.Matches(msg, keys.Submit):
+		name := strings.TrimSpace(m.nameInput.Value())
+		if err := contacts.ValidateGroupName(name); err != nil {
+			m.err = err
+			return m, nil
+		}
+
+		mode := m.prompt
+		m.prompt = groupPromptNone
+		m.nameInput.Blur()
+
+		if mode == groupPromptRename {
+			group, _ := m.selectedGroup()
+			return m, groupCmd(fmt.Sprintf("Renamed @%s to @%s", group.Name, name), func() error {
+				return contacts.RenameGroup(group.Name, name)
+			})
+		}
+		return m, func() tea.Msg {
+			if err := contacts.CreateGroup(name); err != nil {
+				return groupsUpdatedMsg{err: err}
+			}
+			return groupsUpdatedMsg{status: fmt.Sprintf("Created @%s - choose its members", name), open: name}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
+func groupCmd(status string, change func() error) tea.Cmd {
+	return func() tea.Msg {
+		if err := change(); err != nil {
+			return groupsUpdatedMsg{err: err}
+		}
+		return groupsUpdatedMsg{status: status}
+	}
+}
+
+func (m GroupsModel) View() string {
+	if m.showHelp {
+		return helpOverlay("Groups", m.windowWidth, m.fullHelp()...)
+	}
+
+	if m.loading {
+		return "\n  Loading groups...\n"
+	}
+
+	if m.prompt != groupPromptNone {
+		title := "New Group"
+		if m.prompt == groupPromptRename {
+			title = "Rename Group"
+		}
+		s := titleStyle.Render(title) + "\n\n"
+		s += "  " + m.nameInput.View() + "\n\n"
+		if m.err != nil {
+			s += errorStyle.Render(fmt.Sprintf("  %v", m.err)) + "\n\n"
+		}
+		s += helpView(m.windowWidth, keys.Submit, relabel(keys.Back, "cancel"))
+		return s
+	}
+
+	if m.confirmDelete {
+		group, _ := m.selectedGroup()
+		s := titleStyle.Render("Delete Group") + "\n\n"
+		s += normalStyle.Render(fmt.Sprintf("  Delete @%s? Its contacts are kept.", group.Name)) + "\n\n"
+		s += helpView(m.windowWidth, relabel(keys.Confirm, "confirm delete"), keys.Deny, relabel(keys.Back, "cancel"))
+		return s
+	}
+
+	var footer string
+	if m.err != nil {
+		footer += errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n"
+	} else if m.status != "" {
+		footer += statusStyle.Render(m.status) + "\n"
+	}
+
+	if m.editing != "" {
+		if len(m.contacts) == 0 {
+			s := titleStyle.Render("@"+m.editing) + "\n\n"
+			s += normalStyle.Render("  No contacts yet.") + "\n\n"
+			return s + footer + helpView(m.windowWidth, relabel(keys.Back, "back to groups"))
+		}
+		return m.members.View() + "\n" + footer +
+			helpView(m.windowWidth, keys.Help, relabel(keys.Select, "add or remove"), keys.Search, relabel(keys.Back, "back to groups"))
+	}
+
+	if len(m.groups) == 0 {
+		s := titleStyle.Render("Groups") + "\n\n"
+		s += normalStyle.Render("  No groups yet.") + "\n\n"
+		return s + footer + helpView(m.windowWidth, relabel(keys.New, "new group"), keys.Back)
+	}
+
+	return m.list.View() + "\n" + footer +
+		helpView(m.windowWidth, keys.Help, relabel(keys.Open, "edit members"), keys.New, relabel(keys.Edit, "rename"), keys.Delete, keys.Refresh, keys.Back)
+}
+
+func (m GroupsModel) fullHelp() [][]key.Binding {
+	if m.editing != "" {
+		return [][]key.Binding{
+			{keys.Up, keys.Down, keys.Search},
+			{relabel(keys.Select, "add or remove"), relabel(keys.Open, "add or remove")},
+			{relabel(keys.Back, "back to groups"), keys.Help},
+		}
+	}
+	return [][]key.Binding{
+		{keys.Up, keys.Down, relabel(keys.Open, "edit members")},
+		{keys.New, relabel(keys.Edit, "rename"), keys.Delete, keys.Refresh},
+		{keys.Back, keys.Help},
+	}
+}
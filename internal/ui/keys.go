@@ -0,0 +1,388 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyMap holds every key binding in the UI. Screens match keys against it instead of
+// comparing key strings, so a binding changed in config applies everywhere it is used and
+// in the generated help.
+type keyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	Open      key.Binding
+	Submit    key.Binding
+	Back      key.Binding
+	Quit      key.Binding
+	ForceQuit key.Binding
+	Help      key.Binding
+	Search    key.Binding
+	Refresh   key.Binding
+	Confirm   key.Binding
+	Deny      key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+	New       key.Binding
+	Edit      key.Binding
+	Delete    key.Binding
+	Select    key.Binding
+	SelectAll key.Binding
+
+	NextTab      key.Binding
+	PrevTab      key.Binding
+	UnreadFilter key.Binding
+	NextGroup    key.Binding
+	Mute         key.Binding
+	Block        key.Binding
+	ShowBlocked  key.Binding
+
+	Compose        key.Binding
+	AddContact     key.Binding
+	Send           key.Binding
+	InsertTemplate key.Binding
+	UndoSend       key.Binding
+	SendNow        key.Binding
+
+	Chat        key.Binding
+	Import      key.Binding
+	Export      key.Binding
+	ExportAll   key.Binding
+	TagFilter   key.Binding
+	Duplicates  key.Binding
+	Groups      key.Binding
+	Save        key.Binding
+	Favourite   key.Binding
+	AddField    key.Binding
+	RemoveField key.Binding
+	CycleLabel  key.Binding
+	Ignore      key.Binding
+	ShowIgnored key.Binding
+	Skip        key.Binding
+
+	AddRecipient key.Binding
+	Broadcast    key.Binding
+	Personalise  key.Binding
+}
+
+var keys = defaultKeyMap()
+
+func binding(help, desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(help, desc))
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:        binding("↑/k", "up", "up", "k"),
+		Down:      binding("↓/j", "down", "down", "j"),
+		Left:      binding("←/h", "previous", "left", "h"),
+		Right:     binding("→/l", "next", "right", "l"),
+		Open:      binding("enter", "open", "enter"),
+		Submit:    binding("enter", "confirm", "enter"),
+		Back:      binding("esc", "back", "esc"),
+		Quit:      binding("q", "quit", "q"),
+		ForceQuit: binding("ctrl+c", "quit", "ctrl+c"),
+		Help:      binding("?", "help", "?"),
+		Search:    binding("/", "search", "/"),
+		Refresh:   binding("r", "refresh", "r"),
+		Confirm:   binding("y", "confirm", "y", "Y"),
+		Deny:      binding("n", "cancel", "n", "N"),
+		NextField: binding("tab/↓", "next field", "tab", "down"),
+		PrevField: binding("shift+tab/↑", "previous field", "shift+tab", "up"),
+		New:       binding("n", "new", "n", "a"),
+		Edit:      binding("e", "edit", "e"),
+		Delete:    binding("d", "delete", "d", "delete"),
+		Select:    binding("space", "select", " "),
+		SelectAll: binding("A", "select all", "A"),
+
+		NextTab:      binding("tab", "next tab", "tab"),
+		PrevTab:      binding("shift+tab", "previous tab", "shift+tab"),
+		UnreadFilter: binding("u", "unread only", "u"),
+		NextGroup:    binding("g", "next group", "g"),
+		Mute:         binding("m", "mute", "m"),
+		Block:        binding("b", "block", "b"),
+		ShowBlocked:  binding("B", "blocked", "B"),
+
+		Compose:        binding("n", "new message", "n", "c"),
+		AddContact:     binding("a", "add contact", "a"),
+		Send:           binding("ctrl+s", "send", "ctrl+s"),
+		InsertTemplate: binding("ctrl+t", "insert template", "ctrl+t"),
+		UndoSend:       binding("u/esc", "undo", "u", "esc"),
+		SendNow:        binding("s", "send now", "s"),
+
+		Chat:        binding("c", "chat 1:1", "c"),
+		Import:      binding("i", "import", "i"),
+		Export:      binding("x", "export", "x"),
+		ExportAll:   binding("X", "export all", "X"),
+		TagFilter:   binding("t", "filter by tag", "t"),
+		Duplicates:  binding("m", "merge duplicates", "m"),
+		Groups:      binding("g", "groups", "g"),
+		Save:        binding("ctrl+s", "save", "ctrl+s"),
+		Favourite:   binding("ctrl+f", "toggle favourite", "ctrl+f"),
+		AddField:    binding("ctrl+n", "add phone/email", "ctrl+n"),
+		RemoveField: binding("ctrl+x", "remove field", "ctrl+x"),
+		CycleLabel:  binding("ctrl+l", "cycle label", "ctrl+l"),
+		Ignore:      binding("i", "ignore/unignore", "i"),
+		ShowIgnored: binding("I", "show ignored", "I"),
+		Skip:        binding("tab", "skip", "tab"),
+
+		AddRecipient: binding(",", "add recipient", ","),
+		Broadcast:    binding("ctrl+b", "broadcast", "ctrl+b"),
+		Personalise:  binding("ctrl+p", "personalise", "ctrl+p"),
+	}
+}
+
+// actions returns pointers to every binding, keyed by the action name used in config.yml.
+func (k *keyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up": &k.Up, "down": &k.Down, "left": &k.Left, "right": &k.Right,
+		"open": &k.Open, "submit": &k.Submit, "back": &k.Back, "quit": &k.Quit,
+		"force_quit": &k.ForceQuit, "help": &k.Help, "search": &k.Search, "refresh": &k.Refresh,
+		"confirm": &k.Confirm, "deny": &k.Deny, "next_field": &k.NextField, "prev_field": &k.PrevField,
+		"new": &k.New, "edit": &k.Edit, "delete": &k.Delete, "select": &k.Select, "select_all": &k.SelectAll,
+
+		"next_tab": &k.NextTab, "prev_tab": &k.PrevTab, "unread_filter": &k.UnreadFilter,
+		"next_group": &k.NextGroup, "mute": &k.Mute, "block": &k.Block, "show_blocked": &k.ShowBlocked,
+
+		"compose": &k.Compose, "add_contact": &k.AddContact, "send": &k.Send,
+		"insert_template": &k.InsertTemplate, "undo_send": &k.UndoSend, "send_now": &k.SendNow,
+
+		"chat": &k.Chat, "import": &k.Import, "export": &k.Export, "export_all": &k.ExportAll,
+		"tag_filter": &k.TagFilter, "duplicates": &k.Duplicates, "groups": &k.Groups, "save": &k.Save,
+		"favourite": &k.Favourite, "add_field": &k.AddField, "remove_field": &k.RemoveField,
+		"cycle_label": &k.CycleLabel, "ignore": &k.Ignore, "show_ignored": &k.ShowIgnored, "skip": &k.Skip,
+
+		"add_recipient": &k.AddRecipient, "broadcast": &k.Broadcast, "personalise": &k.Personalise,
+	}
+}
+
+// screenKeys lists, for each screen (or mode of one), the actions it checks for a key press.
+// Actions in the same inner list are handled together, so they may share keys; actions in
+// different lists must not.
+var screenKeys = []struct {
+	screen  string
+	actions [][]string
+}{
+	{"menu", [][]string{{"force_quit"}, {"quit"}, {"help"}, {"open"}, {"up"}, {"down"}}},
+	{"conversations", [][]string{
+		{"force_quit"}, {"quit"}, {"help"}, {"back"}, {"refresh"}, {"unread_filter"}, {"next_group"},
+		{"next_tab", "prev_tab"}, {"mute"}, {"block"}, {"show_blocked"}, {"new"}, {"open"},
+		{"up"}, {"down"}, {"search"},
+	}},
+	{"messages", [][]string{{"force_quit"}, {"quit"}, {"help"}, {"back"}, {"compose"}, {"add_contact"}, {"refresh"}, {"up"}, {"down"}}},
+	{"message compose", [][]string{{"force_quit"}, {"back"}, {"send"}, {"insert_template"}}},
+	{"pending message", [][]string{{"force_quit"}, {"undo_send"}, {"edit"}, {"send_now"}}},
+	{"template picker", [][]string{{"force_quit"}, {"back"}, {"open"}, {"up"}, {"down"}, {"search"}}},
+	{"new conversation", [][]string{
+		{"force_quit"}, {"back"}, {"broadcast"}, {"personalise"}, {"add_recipient"},
+		{"next_field", "prev_field"}, {"submit"},
+	}},
+	{"broadcast", [][]string{{"force_quit"}, {"back", "submit", "quit"}}},
+	{"contacts", [][]string{
+		{"force_quit"}, {"quit"}, {"help"}, {"back"}, {"new"}, {"refresh"}, {"import"}, {"export"},
+		{"export_all"}, {"tag_filter"}, {"duplicates"}, {"groups"}, {"open"}, {"edit"}, {"delete"},
+		{"up"}, {"down"}, {"search"},
+	}},
+	{"import preview", [][]string{{"force_quit"}, {"confirm", "submit"}, {"deny", "back"}, {"up"}, {"down"}}},
+	{"contact delete confirmation", [][]string{{"force_quit"}, {"confirm"}, {"deny", "back"}}},
+	{"contact detail", [][]string{{"force_quit"}, {"quit"}, {"help"}, {"back"}, {"refresh"}, {"edit"}, {"open"}, {"chat"}, {"up"}, {"down"}}},
+	{"contact form", [][]string{
+		{"force_quit"}, {"back"}, {"next_field", "prev_field"}, {"save"}, {"favourite"},
+		{"add_field"}, {"remove_field"}, {"cycle_label"},
+	}},
+	{"quick contact form", [][]string{{"force_quit"}, {"back"}, {"submit", "save"}}},
+	{"file prompt", [][]string{{"force_quit"}, {"back"}, {"submit"}}},
+	{"groups", [][]string{
+		{"force_quit"}, {"help"}, {"back", "quit"}, {"new"}, {"edit"}, {"delete"}, {"refresh"}, {"open"},
+		{"up"}, {"down"}, {"search"},
+	}},
+	{"group members", [][]string{{"force_quit"}, {"help"}, {"back"}, {"select", "open"}, {"up"}, {"down"}, {"search"}}},
+	{"duplicates", [][]string{{"force_quit"}, {"help"}, {"back", "quit"}, {"refresh"}, {"open"}, {"up"}, {"down"}}},
+	{"merge", [][]string{{"force_quit"}, {"back"}, {"next_field", "right", "down"}, {"prev_field", "left", "up"}, {"submit", "confirm"}}},
+	{"unknown senders", [][]string{
+		{"force_quit"}, {"help"}, {"back", "quit"}, {"refresh"}, {"select"}, {"select_all"},
+		{"add_contact", "open"}, {"ignore"}, {"show_ignored"}, {"up"}, {"down"}, {"search"},
+	}},
+	{"unknown sender naming", [][]string{{"force_quit"}, {"back"}, {"skip"}, {"submit"}}},
+}
+
+// namedKeys holds the names Bubble Tea gives keys other than single characters, such as
+// "enter", "pgdown" and "ctrl+s".
+var namedKeys = func() map[string]bool {
+	names := make(map[string]bool)
+	for k := tea.KeyF20; k <= tea.KeyCtrlQuestionMark; k++ {
+		if name := k.String(); name != "" && k != tea.KeyRunes && k != tea.KeySpace {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// normalizeKey turns a key as written in config.yml into the name Bubble Tea reports for it:
+// "Ctrl+S" becomes "ctrl+s" and "space" becomes " ". Single characters keep their case, since
+// "R" and "r" are different keys.
+func normalizeKey(k string) (string, error) {
+	written := k
+	k = strings.TrimSpace(k)
+	alt := ""
+	if len(k) > len("alt+") && strings.EqualFold(k[:len("alt+")], "alt+") {
+		alt, k = "alt+", k[len("alt+"):]
+	}
+	if utf8.RuneCountInString(k) > 1 {
+		k = strings.ToLower(k)
+	}
+
+	switch {
+	case k == "":
+		return "", fmt.Errorf("empty key")
+	case k == "space":
+		k = " "
+	case utf8.RuneCountInString(k) == 1:
+		if r, _ := utf8.DecodeRuneInString(k); !unicode.IsPrint(r) {
+			return "", fmt.Errorf("unknown key %q", written)
+		}
+	case !namedKeys[k]:
+		return "", fmt.Errorf("unknown key %q", written)
+	}
+	return alt + k, nil
+}
+
+// checkConflicts reports the first key bound to two actions that a screen handles separately.
+func (k *keyMap) checkConflicts() error {
+	actions := k.actions()
+	for _, screen := range screenKeys {
+		owners := make(map[string]string)
+		groups := make(map[string]int)
+		for i, group := range screen.actions {
+			for _, name := range group {
+				b := actions[name]
+				if !b.Enabled() {
+					continue
+				}
+				for _, bound := range b.Keys() {
+					if owner, ok := owners[bound]; ok && groups[bound] != i {
+						return fmt.Errorf("%s and %s are both bound to %q on the %s screen", owner, name, keyLabel(bound), screen.screen)
+					}
+					owners[bound] = name
+					groups[bound] = i
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyKeyBindings replaces the keys of the named actions with the given ones, e.g.
+// {"refresh": ["r", "f5"]}, on top of the default bindings. An empty list unbinds the action.
+// Key names are checked and normalised, and two actions bound to the same key on one screen
+// are reported.
+func ApplyKeyBindings(overrides map[string][]string) error {
+	km := defaultKeyMap()
+	actions := km.actions()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, ok := actions[name]
+		if !ok {
+			return fmt.Errorf("unknown action %q", name)
+		}
+		if len(overrides[name]) == 0 {
+			b.SetEnabled(false)
+			continue
+		}
+
+		var bound, labels []string
+		for _, written := range overrides[name] {
+			k, err := normalizeKey(written)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			bound = append(bound, k)
+			labels = append(labels, keyLabel(k))
+		}
+		b.SetKeys(bound...)
+		b.SetHelp(strings.Join(labels, "/"), b.Help().Desc)
+	}
+
+	if err := km.checkConflicts(); err != nil {
+		return err
+	}
+	keys = km
+	return nil
+}
+
+func keyLabel(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	}
+	return k
+}
+
+// relabel returns b with its help description replaced by desc.
+func relabel(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// listKeyMap returns the key bindings for a list, using the keymap's navigation and search
+// keys. The list's own quit keys are unbound; each screen decides what quit does.
+func listKeyMap() list.KeyMap {
+	km := list.DefaultKeyMap()
+	km.CursorUp = keys.Up
+	km.CursorDown = keys.Down
+	km.Filter = keys.Search
+	km.Quit.SetEnabled(false)
+	km.ForceQuit.SetEnabled(false)
+	return km
+}
+
+func newHelp(width int) help.Model {
+	h := help.New()
+	h.Width = width
+	keyStyle := helpStyle.Italic(false).Bold(true)
+	h.Styles.ShortKey = keyStyle
+	h.Styles.ShortDesc = helpStyle
+	h.Styles.ShortSeparator = helpStyle
+	h.Styles.FullKey = keyStyle
+	h.Styles.FullDesc = helpStyle
+	h.Styles.FullSeparator = helpStyle
+	h.Styles.Ellipsis = helpStyle
+	return h
+}
+
+// helpView renders one line of help for bindings, cut to fit width.
+func helpView(width int, bindings ...key.Binding) string {
+	return newHelp(width).ShortHelpView(bindings)
+}
+
+// helpOverlay renders the full help for a screen: every binding, in columns.
+func helpOverlay(title string, width int, groups ...[]key.Binding) string {
+	s := titleStyle.Render(title+" - Keys") + "\n\n"
+	s += newHelp(width).FullHelpView(groups) + "\n\n"
+	s += helpStyle.Render("Press any key to close")
+	return s
+}
@@ -1,9 +1,9 @@
 package ui
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 type menuItem struct {
@@ -19,27 +19,30 @@ type MenuModel struct {
 	list         list.Model
 	windowWidth  int
 	windowHeight int
+	showHelp     bool
 }
 
-// NewMenuModel creates the main menu with Conversations and Contacts options.
+// NewMenuModel creates the main menu with Conversations, Contacts and Unknown Senders options.
 func NewMenuModel() MenuModel {
 	items := []list.Item{
 		menuItem{title: "💬 Conversations", desc: "View and send messages"},
 		menuItem{title: "👥 Contacts", desc: "Manage your contacts"},
+		menuItem{title: "❓ Unknown Senders", desc: "Add people who aren't in your contacts yet"},
 	}
 
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
-		Foreground(lipgloss.Color("5")).
+		Foreground(accentColor).
 		Bold(true)
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
-		Foreground(lipgloss.Color("8"))
+		Foreground(dimColor)
 
 	l := list.New(items, delegate, 80, 14)
 	l.Title = "Chime - iMessage Terminal Client"
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
 	l.SetShowHelp(false)
+	l.KeyMap = listKeyMap()
 
 	return MenuModel{
 		list:         l,
@@ -48,6 +51,19 @@ func NewMenuModel() MenuModel {
 	}
 }
 
+// NewStartModel creates the screen shown at startup for a default_view setting.
+func NewStartModel(view string) tea.Model {
+	switch view {
+	case "conversations":
+		return NewConversationsModel()
+	case "contacts":
+		return NewContactsListModel()
+	case "unknown-senders":
+		return NewUnknownHandlesModel()
+	}
+	return NewMenuModel()
+}
+
 func (m MenuModel) Init() tea.Cmd {
 	return nil
 }
@@ -62,11 +78,24 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" || msg.String() == "q" {
+		if key.Matches(msg, keys.ForceQuit) {
 			return m, tea.Quit
 		}
 
-		if msg.String() == "enter" {
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, keys.Open):
 			selectedItem, ok := m.list.SelectedItem().(menuItem)
 			if !ok {
 				return m, nil
@@ -86,6 +115,13 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					contactsModel = updatedModel.(ContactsListModel)
 				}
 				return contactsModel, contactsModel.Init()
+			} else if selectedItem.title == "❓ Unknown Senders" {
+				unknownModel := NewUnknownHandlesModel()
+				if m.windowWidth > 0 {
+					updatedModel, _ := unknownModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+					unknownModel = updatedModel.(UnknownHandlesModel)
+				}
+				return unknownModel, unknownModel.Init()
 			}
 		}
 
@@ -100,7 +136,11 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m MenuModel) View() string {
+	if m.showHelp {
+		return helpOverlay("Chime", m.windowWidth, []key.Binding{keys.Up, keys.Down, relabel(keys.Open, "select")}, []key.Binding{keys.Help, keys.Quit})
+	}
+
 	s := m.list.View() + "\n"
-	s += helpStyle.Render("↑↓/jk: navigate • enter: select • q: quit")
+	s += helpView(m.windowWidth, keys.Up, keys.Down, relabel(keys.Open, "select"), keys.Help, keys.Quit)
 	return s
 }
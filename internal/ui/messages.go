@@ -5,15 +5,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/reflow/wordwrap"
+	"github.com/saravenpi/chime/internal/config"
 	"github.com/saravenpi/chime/internal/contacts"
 	"github.com/saravenpi/chime/internal/imessage"
 	"github.com/saravenpi/chime/internal/models"
+	"github.com/saravenpi/chime/internal/resolver"
+	"github.com/saravenpi/chime/internal/templates"
 )
 
 type messagesFetchedMsg struct {
@@ -22,38 +27,75 @@ type messagesFetchedMsg struct {
 }
 
 type messageSentMsg struct {
-	err error
+	text       string
+	afterRowID int64
+	// noBaseline is set when chat.db's latest ROWID couldn't be read before sending, so the
+	// message can't be told apart from older ones with the same text and isn't verified.
+	noBaseline bool
+	err        error
+}
+
+// UndoSendDelay is how long a message waits as a pending bubble before it is handed to
+// Messages.app, giving time to cancel or edit it. Zero sends immediately.
+var UndoSendDelay = 5 * time.Second
+
+type pendingSendTickMsg struct {
+	id int
+}
+
+type pendingMessage struct {
+	id       int
+	text     string
+	deadline time.Time
+}
+
+type messageVerifiedMsg struct {
+	status imessage.SendStatus
+	err    error
 }
 
 type MessagesModel struct {
-	chat           models.Chat
-	messages       []models.Message
-	viewport       viewport.Model
-	textarea       textarea.Model
-	loading        bool
-	sending        bool
-	composing      bool
-	err            error
-	spinner        spinner.Model
-	windowWidth    int
-	windowHeight   int
-	viewportReady  bool
-	showUnreadOnly bool
+	chat            models.Chat
+	messages        []models.Message
+	viewport        viewport.Model
+	textarea        textarea.Model
+	loading         bool
+	sending         bool
+	composing       bool
+	err             error
+	spinner         spinner.Model
+	windowWidth     int
+	windowHeight    int
+	viewportReady   bool
+	filters         conversationFilters
+	verifying       bool
+	sendStatus      *imessage.SendStatus
+	pickingTemplate bool
+	templatePicker  list.Model
+	pending         *pendingMessage
+	pendingCount    int
+	showHelp        bool
 }
 
-func NewMessagesModel(chat models.Chat, showUnreadOnly bool) MessagesModel {
+func NewMessagesModel(chat models.Chat, filters conversationFilters) MessagesModel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = statusStyle
 
 	vp := viewport.New(80, 20)
 	vp.HighPerformanceRendering = false
+	vp.KeyMap.Up = keys.Up
+	vp.KeyMap.Down = keys.Down
 
 	ta := textarea.New()
 	ta.Placeholder = "Type your message..."
-	ta.CharLimit = 1000
+	ta.CharLimit = config.Current().Send.CharLimit
 	ta.SetHeight(3)
 	ta.ShowLineNumbers = false
+	if config.Current().Send.EnterSends {
+		ta.KeyMap.InsertNewline.SetKeys("alt+enter", "ctrl+j")
+		ta.KeyMap.InsertNewline.SetHelp("alt+enter", "new line")
+	}
 
 	return MessagesModel{
 		chat:           chat,
@@ -64,7 +106,8 @@ func NewMessagesModel(chat models.Chat, showUnreadOnly bool) MessagesModel {
 		windowWidth:    80,
 		windowHeight:   30,
 		viewportReady:  true,
-		showUnreadOnly: showUnreadOnly,
+		filters:        filters,
+		templatePicker: newTemplatePicker(80, 20),
 	}
 }
 
@@ -88,8 +131,16 @@ func (m MessagesModel) fetchMessagesCmd() tea.Cmd {
 
 func (m MessagesModel) sendMessageCmd(message string) tea.Cmd {
 	return func() tea.Msg {
+		afterRowID, baselineErr := imessage.GetLatestMessageRowID()
 		err := imessage.SendMessageToChat(m.chat, message)
-		return messageSentMsg{err: err}
+		return messageSentMsg{text: message, afterRowID: afterRowID, noBaseline: baselineErr != nil, err: err}
+	}
+}
+
+func (m MessagesModel) verifySendCmd(text string, afterRowID int64) tea.Cmd {
+	return func() tea.Msg {
+		status, err := imessage.VerifySend(m.chat.ChatID, text, afterRowID, config.Current().Send.VerifyTimeout)
+		return messageVerifiedMsg{status: status, err: err}
 	}
 }
 
@@ -107,6 +158,16 @@ func (m MessagesModel) canAddContact() bool {
 	return existingName == ""
 }
 
+// templateVars returns the template variables for the current chat.
+// 1:1 chats use the contact's name; group chats leave the name variables empty.
+func (m MessagesModel) templateVars() map[string]string {
+	name := ""
+	if !m.chat.IsGroup && len(m.chat.Participants) > 0 {
+		name = resolver.Lookup(m.chat.Participants[0])
+	}
+	return templates.Vars(name, time.Now())
+}
+
 func (m MessagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -127,9 +188,30 @@ func (m MessagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.Height = availableHeight
 		}
 
+		m.templatePicker.SetSize(msg.Width-4, availableHeight)
 		m.updateViewportContent()
 		return m, nil
 
+	case templatesLoadedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.pickingTemplate = false
+			return m, nil
+		}
+
+		items := make([]list.Item, len(msg.templates))
+		for i, t := range msg.templates {
+			items[i] = templateItem{template: t}
+		}
+		m.templatePicker.SetItems(items)
+		return m, nil
+
+	case ContactsChangedMsg:
+		if !m.chat.IsGroup {
+			m.chat.DisplayName = resolver.DisplayName(m.chat.ChatID)
+		}
+		return m, m.fetchMessagesCmd()
+
 	case messagesFetchedMsg:
 		m.loading = false
 		if msg.err != nil {
@@ -152,10 +234,37 @@ func (m MessagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.textarea.Reset()
 		m.composing = false
 		m.loading = true
-		return m, tea.Batch(m.spinner.Tick, tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
+		if msg.noBaseline {
+			status := imessage.SendStatusUnconfirmed
+			m.sendStatus = &status
+			return m, tea.Batch(m.spinner.Tick, m.fetchMessagesCmd())
+		}
+		m.verifying = true
+		m.sendStatus = nil
+		return m, tea.Batch(m.spinner.Tick, m.verifySendCmd(msg.text, msg.afterRowID), tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
 			return m.fetchMessagesCmd()()
 		}))
 
+	case pendingSendTickMsg:
+		if m.pending == nil || m.pending.id != msg.id {
+			return m, nil
+		}
+		if time.Now().Before(m.pending.deadline) {
+			return m, m.pendingTickCmd(msg.id)
+		}
+		return m.sendPending()
+
+	case messageVerifiedMsg:
+		m.verifying = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+
+		status := msg.status
+		m.sendStatus = &status
+		return m, m.fetchMessagesCmd()
+
 	case spinner.TickMsg:
 		if m.loading || m.sending {
 			var cmd tea.Cmd
@@ -165,20 +274,69 @@ func (m MessagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
+		if key.Matches(msg, keys.ForceQuit) {
 			return m, tea.Quit
 		}
 
-		if msg.String() == "esc" {
-			if m.composing {
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		if m.pickingTemplate {
+			return m.updateTemplatePicker(msg)
+		}
+
+		if m.pending != nil {
+			switch {
+			case key.Matches(msg, keys.UndoSend):
+				m.pending = nil
+				m.textarea.Reset()
+				return m, nil
+			case key.Matches(msg, keys.Edit):
+				m.textarea.SetValue(m.pending.text)
+				m.pending = nil
+				m.composing = true
+				m.textarea.Focus()
+				return m, textarea.Blink
+			case key.Matches(msg, keys.SendNow):
+				return m.sendPending()
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+		if m.composing {
+			switch {
+			case key.Matches(msg, keys.Back):
 				m.composing = false
 				m.textarea.Reset()
 				m.textarea.Blur()
 				m.err = nil
 				return m, nil
+			case key.Matches(msg, sendKey()):
+				return m.submitMessage()
+			case key.Matches(msg, keys.InsertTemplate):
+				m.pickingTemplate = true
+				m.templatePicker.ResetFilter()
+				return m, loadTemplatesCmd()
 			}
-			convModel := NewConversationsModel()
-			convModel.showUnreadOnly = m.showUnreadOnly
+			var cmd tea.Cmd
+			m.textarea, cmd = m.textarea.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, keys.Back):
+			convModel := newFilteredConversationsModel(m.filters)
 			if m.windowWidth > 0 {
 				updatedModel, cmd := convModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 				convModel = updatedModel.(ConversationsModel)
@@ -187,40 +345,19 @@ func (m MessagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return convModel, convModel.Init()
 		}
 
-		if m.composing {
-			switch msg.String() {
-			case "ctrl+s":
-				messageText := strings.TrimSpace(m.textarea.Value())
-				if messageText != "" {
-					m.sending = true
-					m.composing = false
-					m.textarea.Blur()
-					return m, tea.Batch(
-						m.spinner.Tick,
-						m.sendMessageCmd(messageText),
-					)
-				}
-				return m, nil
-			default:
-				var cmd tea.Cmd
-				m.textarea, cmd = m.textarea.Update(msg)
-				return m, cmd
-			}
-		}
-
 		if m.loading || m.sending {
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "n", "c":
+		switch {
+		case key.Matches(msg, keys.Compose):
 			m.composing = true
 			m.textarea.Focus()
 			return m, textarea.Blink
 
-		case "a":
+		case key.Matches(msg, keys.AddContact):
 			if m.canAddContact() && len(m.chat.Participants) > 0 {
-				quickForm := NewQuickContactFormModel(m.chat, m.chat.Participants[0], m.showUnreadOnly)
+				quickForm := NewQuickContactFormModel(m.chat, m.chat.Participants[0], m.filters)
 				if m.windowWidth > 0 {
 					updatedModel, _ := quickForm.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 					quickForm = updatedModel.(QuickContactFormModel)
@@ -229,20 +366,94 @@ func (m MessagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "r":
+		case key.Matches(msg, keys.Refresh):
 			m.loading = true
 			return m, tea.Batch(m.spinner.Tick, m.fetchMessagesCmd())
-
-		default:
-			var cmd tea.Cmd
-			m.viewport, cmd = m.viewport.Update(msg)
-			return m, cmd
 		}
+
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
 	}
 
 	return m, nil
 }
 
+// sendKey returns the binding that sends the composed message: the send action, and enter
+// as well when send.enter_sends is set.
+func sendKey() key.Binding {
+	if !config.Current().Send.EnterSends {
+		return keys.Send
+	}
+	return key.NewBinding(
+		key.WithKeys(append([]string{"enter"}, keys.Send.Keys()...)...),
+		key.WithHelp("enter", keys.Send.Help().Desc),
+	)
+}
+
+// submitMessage sends the composed message, holding it as a pending bubble first when
+// UndoSendDelay is set.
+func (m MessagesModel) submitMessage() (tea.Model, tea.Cmd) {
+	messageText := strings.TrimSpace(m.textarea.Value())
+	if messageText == "" {
+		return m, nil
+	}
+
+	m.composing = false
+	m.textarea.Blur()
+	if UndoSendDelay > 0 {
+		m.pendingCount++
+		m.pending = &pendingMessage{
+			id:       m.pendingCount,
+			text:     messageText,
+			deadline: time.Now().Add(UndoSendDelay),
+		}
+		return m, m.pendingTickCmd(m.pendingCount)
+	}
+	m.sending = true
+	return m, tea.Batch(
+		m.spinner.Tick,
+		m.sendMessageCmd(messageText),
+	)
+}
+
+func (m MessagesModel) pendingTickCmd(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return pendingSendTickMsg{id: id}
+	})
+}
+
+// sendPending hands the pending message to Messages.app once its grace period is over.
+func (m MessagesModel) sendPending() (tea.Model, tea.Cmd) {
+	text := m.pending.text
+	m.pending = nil
+	m.sending = true
+	return m, tea.Batch(m.spinner.Tick, m.sendMessageCmd(text))
+}
+
+// updateTemplatePicker handles keys while the template picker is open.
+// Enter inserts the selected template, expanded for this chat, at the cursor.
+func (m MessagesModel) updateTemplatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.templatePicker.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(msg, keys.Back):
+			m.pickingTemplate = false
+			return m, nil
+
+		case key.Matches(msg, keys.Open):
+			if item, ok := m.templatePicker.SelectedItem().(templateItem); ok {
+				m.textarea.InsertString(templates.Expand(item.template.Body, m.templateVars()))
+			}
+			m.pickingTemplate = false
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.templatePicker, cmd = m.templatePicker.Update(msg)
+	return m, cmd
+}
+
 func (m *MessagesModel) updateViewportContent() {
 	if !m.viewportReady || len(m.messages) == 0 {
 		return
@@ -259,7 +470,7 @@ func (m *MessagesModel) updateViewportContent() {
 			content.WriteString("\n")
 		}
 
-		timestamp := message.Date.Format("3:04 PM")
+		timestamp := message.Date.Format(config.Current().ClockLayout())
 
 		if message.IsFromMe {
 			sender := "You"
@@ -302,10 +513,23 @@ func (m *MessagesModel) updateViewportContent() {
 }
 
 func (m MessagesModel) View() string {
+	if m.showHelp {
+		return helpOverlay(m.chat.DisplayName, m.windowWidth, m.fullHelp()...)
+	}
+
 	if m.loading && len(m.messages) == 0 {
 		return fmt.Sprintf("\n  %s Loading messages...\n", m.spinner.View())
 	}
 
+	if m.pickingTemplate {
+		s := m.templatePicker.View() + "\n"
+		if len(m.templatePicker.Items()) == 0 {
+			s += normalStyle.Render(fmt.Sprintf("  No templates found in %s", templates.GetTemplatesDir())) + "\n"
+		}
+		s += helpView(m.windowWidth, keys.Up, keys.Down, relabel(keys.Open, "insert"), keys.Search, relabel(keys.Back, "cancel"))
+		return s
+	}
+
 	s := titleStyle.Render(fmt.Sprintf("💬 %s", m.chat.DisplayName)) + "\n\n"
 
 	if m.err != nil {
@@ -320,18 +544,76 @@ func (m MessagesModel) View() string {
 		s += m.viewport.View() + "\n"
 	}
 
-	if m.composing {
+	if m.pending != nil {
+		s += m.renderPendingMessage() + "\n"
+	}
+
+	if m.verifying {
+		s += statusStyle.Render("  Waiting for Messages.app to confirm delivery...") + "\n"
+	} else if m.sendStatus != nil {
+		s += renderSendStatus(*m.sendStatus) + "\n"
+	}
+
+	if m.pending != nil {
+		s += "\n" + helpView(m.windowWidth, keys.UndoSend, keys.Edit, keys.SendNow)
+	} else if m.composing {
 		s += "\n" + inputStyle.Render("New Message:") + "\n"
 		s += m.textarea.View() + "\n"
-		s += helpStyle.Render("ctrl+s: send • esc: cancel")
+		bindings := []key.Binding{sendKey()}
+		if config.Current().Send.EnterSends {
+			bindings = append(bindings, m.textarea.KeyMap.InsertNewline)
+		}
+		bindings = append(bindings, keys.InsertTemplate, relabel(keys.Back, "cancel"))
+		s += helpView(m.windowWidth, bindings...)
 	} else {
 		scrollPercent := int(m.viewport.ScrollPercent() * 100)
-		helpText := fmt.Sprintf("↑↓/jk: scroll • n: new message • r: refresh • esc: back • q: quit • %d%%", scrollPercent)
+		bindings := []key.Binding{keys.Help, keys.Compose}
 		if m.canAddContact() {
-			helpText = fmt.Sprintf("↑↓/jk: scroll • n: new message • a: add contact • r: refresh • esc: back • q: quit • %d%%", scrollPercent)
+			bindings = append(bindings, keys.AddContact)
 		}
-		s += "\n" + helpStyle.Render(helpText)
+		bindings = append(bindings, keys.Refresh, keys.Back, keys.Quit)
+		s += "\n" + helpView(m.windowWidth, bindings...) + helpStyle.Render(fmt.Sprintf(" • %d%%", scrollPercent))
 	}
 
 	return s
 }
+
+func (m MessagesModel) fullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{relabel(keys.Up, "scroll up"), relabel(keys.Down, "scroll down"), keys.Refresh},
+		{keys.Compose, sendKey(), keys.InsertTemplate, keys.AddContact},
+		{keys.UndoSend, relabel(keys.Edit, "edit pending"), keys.SendNow},
+		{keys.Back, keys.Quit, keys.Help},
+	}
+}
+
+// renderPendingMessage draws the message waiting out its undo grace period, with a countdown.
+func (m MessagesModel) renderPendingMessage() string {
+	width := m.viewport.Width
+	if width <= 0 {
+		width = 80
+	}
+
+	remaining := int(time.Until(m.pending.deadline).Round(time.Second).Seconds())
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	header := messageHeaderStyle.Render(fmt.Sprintf("You • sending in %ds", remaining))
+	body := helpStyle.Render(wordwrap.String(m.pending.text, width-10))
+
+	right := lipgloss.NewStyle().Align(lipgloss.Right).Width(width)
+	return right.Render(header) + "\n" + right.Render(body)
+}
+
+// renderSendStatus describes the outcome of the last send as reported by chat.db.
+func renderSendStatus(status imessage.SendStatus) string {
+	switch status {
+	case imessage.SendStatusSent:
+		return statusStyle.Render("  ✓ Last message sent")
+	case imessage.SendStatusFailed:
+		return errorStyle.Render("  ✗ Last message failed to send")
+	default:
+		return helpStyle.Render("  ? Last message unconfirmed - Messages.app has not reported it as sent yet")
+	}
+}
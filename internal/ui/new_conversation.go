@@ -1,40 +1,83 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/saravenpi/chime/internal/config"
+	"github.com/saravenpi/chime/internal/contacts"
 	"github.com/saravenpi/chime/internal/imessage"
 	"github.com/saravenpi/chime/internal/models"
+	"github.com/saravenpi/chime/internal/resolver"
 )
 
+const maxRecipientSuggestions = 5
+
+// recipient is a handle chosen on the new conversation screen, with the contact name if known.
+type recipient struct {
+	name   string
+	handle string
+}
+
+func (r recipient) label() string {
+	if r.name != "" && r.name != r.handle {
+		return fmt.Sprintf("%s (%s)", r.name, r.handle)
+	}
+	return r.handle
+}
+
+type groupChatCreatedMsg struct {
+	// chat is nil when the message was sent but the new group hadn't appeared in chat.db yet.
+	chat  *models.Chat
+	names []string
+	err   error
+}
+
 type NewConversationModel struct {
-	recipientInput textinput.Model
-	messageInput   textinput.Model
-	focusIndex     int
-	windowWidth    int
-	windowHeight   int
-	showUnreadOnly bool
-	err            error
+	recipients      []recipient
+	suggestions     []recipient
+	suggestionIndex int
+	recipientInput  textinput.Model
+	messageInput    textinput.Model
+	focusIndex      int
+	windowWidth     int
+	windowHeight    int
+	filters         conversationFilters
+	broadcast       bool
+	personalize     bool
+	sending         bool
+	spinner         spinner.Model
+	err             error
 }
 
-func NewNewConversationModel(showUnreadOnly bool) NewConversationModel {
+func NewNewConversationModel(filters conversationFilters) NewConversationModel {
 	recipientInput := textinput.New()
-	recipientInput.Placeholder = "Phone number or email (e.g., +1234567890 or user@example.com)"
+	recipientInput.Placeholder = "Name, phone number, email or @group - enter or comma to add"
 	recipientInput.Focus()
 	recipientInput.CharLimit = 100
 	recipientInput.Width = 60
 
 	messageInput := textinput.New()
 	messageInput.Placeholder = "Type your message here..."
-	messageInput.CharLimit = 1000
+	messageInput.CharLimit = config.Current().Send.CharLimit
 	messageInput.Width = 60
 
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = statusStyle
+
 	return NewConversationModel{
 		recipientInput: recipientInput,
 		messageInput:   messageInput,
 		focusIndex:     0,
-		showUnreadOnly: showUnreadOnly,
+		filters:        filters,
+		spinner:        s,
 	}
 }
 
@@ -42,6 +85,154 @@ func (m NewConversationModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
+// startGroupChatCmd creates the group through the send backend, then waits briefly for
+// Messages.app to write the new chat to chat.db so the messages view can open it. A chat
+// that doesn't show up in time is never opened, since it can't be told apart from a new one.
+func (m NewConversationModel) startGroupChatCmd(message string) tea.Cmd {
+	recipients := m.recipients
+	return func() tea.Msg {
+		handles := make([]string, len(recipients))
+		names := make([]string, len(recipients))
+		for i, r := range recipients {
+			handles[i] = r.handle
+			names[i] = r.handle
+			if r.name != "" {
+				names[i] = r.name
+			}
+		}
+
+		if err := imessage.StartGroupChat(handles, message); err != nil {
+			return groupChatCreatedMsg{err: err}
+		}
+
+		for attempt := 0; attempt < 5; attempt++ {
+			chat, err := imessage.FindGroupChat(handles)
+			if err == nil && chat != nil {
+				return groupChatCreatedMsg{chat: chat}
+			}
+			time.Sleep(time.Second)
+		}
+
+		return groupChatCreatedMsg{names: names}
+	}
+}
+
+// updateSuggestions refreshes the contact autocompletion for the recipient input.
+func (m *NewConversationModel) updateSuggestions() {
+	m.suggestions = nil
+	m.suggestionIndex = 0
+
+	query := strings.ToLower(strings.TrimSpace(m.recipientInput.Value()))
+	if query == "" {
+		return
+	}
+
+	if strings.HasPrefix(query, "@") {
+		groups, err := contacts.ListGroups()
+		if err != nil {
+			return
+		}
+		for _, group := range groups {
+			if strings.HasPrefix(strings.ToLower(group.Name), query[1:]) {
+				m.suggestions = append(m.suggestions, recipient{handle: "@" + group.Name})
+				if len(m.suggestions) >= maxRecipientSuggestions {
+					return
+				}
+			}
+		}
+		return
+	}
+
+	allContacts, err := contacts.ListContacts()
+	if err != nil {
+		return
+	}
+
+	chosen := make(map[string]bool, len(m.recipients))
+	for _, r := range m.recipients {
+		chosen[r.handle] = true
+	}
+
+	for _, contact := range allContacts {
+		nameMatches := strings.Contains(strings.ToLower(contact.Name), query)
+		identifiers := append(append([]string{}, contact.PhoneNumbers...), contact.Emails...)
+		for _, identifier := range identifiers {
+			if chosen[identifier] {
+				continue
+			}
+			if !nameMatches && !strings.Contains(strings.ToLower(identifier), query) {
+				continue
+			}
+			m.suggestions = append(m.suggestions, recipient{name: contact.Name, handle: identifier})
+			if len(m.suggestions) >= maxRecipientSuggestions {
+				return
+			}
+		}
+	}
+}
+
+// addRecipientFromInput turns the recipient input into a chip, preferring the highlighted
+// suggestion and otherwise resolving the typed handle or contact name. "@group" adds every
+// member of the group and switches to broadcast mode.
+func (m *NewConversationModel) addRecipientFromInput() error {
+	value := strings.TrimSpace(m.recipientInput.Value())
+	if value == "" {
+		return nil
+	}
+
+	var r recipient
+	if len(m.suggestions) > 0 {
+		r = m.suggestions[m.suggestionIndex]
+	} else if strings.HasPrefix(value, "@") {
+		r = recipient{handle: value}
+	} else {
+		resolved, err := resolveRecipient(value)
+		if err != nil {
+			return err
+		}
+		r = resolved
+	}
+
+	toAdd := []recipient{r}
+	if strings.HasPrefix(r.handle, "@") {
+		members, err := resolveGroupRecipients(strings.TrimPrefix(r.handle, "@"))
+		if err != nil {
+			return err
+		}
+		toAdd = members
+		m.broadcast = true
+	}
+
+	for _, r := range toAdd {
+		if !m.hasRecipient(r.handle) {
+			m.recipients = append(m.recipients, r)
+		}
+	}
+	m.recipientInput.SetValue("")
+	m.updateSuggestions()
+	return nil
+}
+
+func (m NewConversationModel) hasRecipient(handle string) bool {
+	for _, existing := range m.recipients {
+		if existing.handle == handle {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *NewConversationModel) setFocus(index int) {
+	m.focusIndex = index
+	if m.focusIndex == 0 {
+		m.recipientInput.Focus()
+		m.messageInput.Blur()
+	} else {
+		m.recipientInput.Blur()
+		m.messageInput.Focus()
+	}
+}
+
 func (m NewConversationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -51,89 +242,200 @@ func (m NewConversationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.messageInput.Width = msg.Width - 20
 		return m, nil
 
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return m, tea.Quit
+	case spinner.TickMsg:
+		if m.sending {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
 
-		case "esc":
-			conversationsModel := NewConversationsModel()
-			conversationsModel.showUnreadOnly = m.showUnreadOnly
+	case groupChatCreatedMsg:
+		m.sending = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+
+		if msg.chat == nil {
+			conversationsModel := newFilteredConversationsModel(m.filters)
+			conversationsModel.status = fmt.Sprintf("Sent to %s - the group will appear here once Messages.app has created it", strings.Join(msg.names, ", "))
 			if m.windowWidth > 0 {
 				updatedModel, _ := conversationsModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 				conversationsModel = updatedModel.(ConversationsModel)
 			}
 			return conversationsModel, conversationsModel.Init()
+		}
 
-		case "tab", "shift+tab":
-			if msg.String() == "tab" {
-				m.focusIndex = (m.focusIndex + 1) % 2
-			} else {
-				m.focusIndex = (m.focusIndex - 1 + 2) % 2
-			}
+		messagesModel := NewMessagesModel(*msg.chat, m.filters)
+		if m.windowWidth > 0 {
+			updatedModel, _ := messagesModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+			messagesModel = updatedModel.(MessagesModel)
+		}
+		return messagesModel, messagesModel.Init()
 
-			if m.focusIndex == 0 {
-				m.recipientInput.Focus()
-				m.messageInput.Blur()
-			} else {
-				m.recipientInput.Blur()
-				m.messageInput.Focus()
+	case tea.KeyMsg:
+		if m.sending {
+			if key.Matches(msg, keys.ForceQuit) {
+				return m, tea.Quit
 			}
 			return m, nil
+		}
 
-		case "enter":
-			if m.recipientInput.Value() == "" {
-				m.err = nil
-				return m, nil
+		switch {
+		case key.Matches(msg, keys.ForceQuit):
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Back):
+			conversationsModel := newFilteredConversationsModel(m.filters)
+			if m.windowWidth > 0 {
+				updatedModel, _ := conversationsModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+				conversationsModel = updatedModel.(ConversationsModel)
 			}
+			return conversationsModel, conversationsModel.Init()
 
-			if m.messageInput.Value() == "" {
-				m.err = nil
-				return m, nil
+		// Suggestion keys come first: PrevSuggestion includes ctrl+p, the default personalise key.
+		case key.Matches(msg, m.recipientInput.KeyMap.NextSuggestion) && m.focusIndex == 0 && len(m.suggestions) > 0:
+			m.suggestionIndex = (m.suggestionIndex + 1) % len(m.suggestions)
+			return m, nil
+
+		case key.Matches(msg, m.recipientInput.KeyMap.PrevSuggestion) && m.focusIndex == 0 && len(m.suggestions) > 0:
+			m.suggestionIndex = (m.suggestionIndex - 1 + len(m.suggestions)) % len(m.suggestions)
+			return m, nil
+
+		case key.Matches(msg, keys.Broadcast):
+			m.broadcast = !m.broadcast
+			m.err = nil
+			return m, nil
+
+		case key.Matches(msg, keys.Personalise):
+			if m.broadcast {
+				m.personalize = !m.personalize
 			}
+			return m, nil
 
-			recipient := m.recipientInput.Value()
-			message := m.messageInput.Value()
+		case key.Matches(msg, keys.AddRecipient) && m.focusIndex == 0:
+			m.err = m.addRecipientFromInput()
+			return m, nil
+
+		case msg.Type == tea.KeyBackspace && m.focusIndex == 0 && m.recipientInput.Value() == "" && len(m.recipients) > 0:
+			m.recipients = m.recipients[:len(m.recipients)-1]
+			return m, nil
 
-			err := imessage.SendMessage(recipient, message)
-			if err != nil {
-				m.err = err
+		case key.Matches(msg, keys.NextField, keys.PrevField):
+			if key.Matches(msg, keys.NextField) && m.focusIndex == 0 && len(m.suggestions) > 0 {
+				m.err = m.addRecipientFromInput()
 				return m, nil
 			}
+			m.setFocus((m.focusIndex + 1) % 2)
+			return m, nil
 
-			chat := models.Chat{
-				ChatID:      recipient,
-				DisplayName: recipient,
-				IsGroup:     false,
+		case key.Matches(msg, keys.Submit):
+			if m.focusIndex == 0 {
+				if strings.TrimSpace(m.recipientInput.Value()) != "" {
+					m.err = m.addRecipientFromInput()
+					return m, nil
+				}
+				if len(m.recipients) > 0 {
+					m.setFocus(1)
+				}
+				return m, nil
 			}
 
-			messagesModel := NewMessagesModel(chat, m.showUnreadOnly)
-			if m.windowWidth > 0 {
-				updatedModel, _ := messagesModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
-				messagesModel = updatedModel.(MessagesModel)
-			}
-			return messagesModel, messagesModel.Init()
+			return m.send()
 		}
 	}
 
 	var cmd tea.Cmd
 	if m.focusIndex == 0 {
+		previous := m.recipientInput.Value()
 		m.recipientInput, cmd = m.recipientInput.Update(msg)
+		if m.recipientInput.Value() != previous {
+			m.updateSuggestions()
+		}
 	} else {
 		m.messageInput, cmd = m.messageInput.Update(msg)
 	}
 	return m, cmd
 }
 
+// send delivers the message: individually to each recipient in broadcast mode,
+// to a single 1:1 chat, or to a new group chat when there are several recipients.
+func (m NewConversationModel) send() (tea.Model, tea.Cmd) {
+	if err := m.addRecipientFromInput(); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	message := m.messageInput.Value()
+	if len(m.recipients) == 0 || strings.TrimSpace(message) == "" {
+		m.err = nil
+		return m, nil
+	}
+
+	if m.broadcast {
+		broadcastModel := NewBroadcastModel(m.recipients, message, m.personalize, m.filters)
+		if m.windowWidth > 0 {
+			updatedModel, _ := broadcastModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+			broadcastModel = updatedModel.(BroadcastModel)
+		}
+		return broadcastModel, broadcastModel.Init()
+	}
+
+	if len(m.recipients) > 1 {
+		m.sending = true
+		m.err = nil
+		return m, tea.Batch(m.spinner.Tick, m.startGroupChatCmd(message))
+	}
+
+	recipient := m.recipients[0].handle
+	err := imessage.SendMessage(recipient, message)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	chat := models.Chat{
+		ChatID:       recipient,
+		DisplayName:  recipient,
+		Participants: []string{recipient},
+		IsGroup:      false,
+	}
+	if m.recipients[0].name != "" {
+		chat.DisplayName = m.recipients[0].name
+	}
+
+	messagesModel := NewMessagesModel(chat, m.filters)
+	if m.windowWidth > 0 {
+		updatedModel, _ := messagesModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+		messagesModel = updatedModel.(MessagesModel)
+	}
+	return messagesModel, messagesModel.Init()
+}
+
 func (m NewConversationModel) View() string {
 	style := lipgloss.NewStyle().
 		Padding(1, 2).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("5"))
+		BorderForeground(accentColor)
+
+	chipStyle := lipgloss.NewStyle().
+		Foreground(accentTextColor).
+		Background(accentColor).
+		Padding(0, 1)
 
 	title := titleStyle.Render("New Conversation")
+	if m.broadcast {
+		personalizeStatus := "off"
+		if m.personalize {
+			personalizeStatus = "on"
+		}
+		title = titleStyle.Render(fmt.Sprintf("New Broadcast (personalise: %s)", personalizeStatus))
+	} else if len(m.recipients) > 1 {
+		title = titleStyle.Render(fmt.Sprintf("New Group Conversation (%d people)", len(m.recipients)))
+	}
 
-	recipientLabel := "Recipient:"
+	recipientLabel := "Recipients:"
 	if m.focusIndex == 0 {
 		recipientLabel = "> " + recipientLabel
 	} else {
@@ -147,19 +449,117 @@ func (m NewConversationModel) View() string {
 		messageLabel = "  " + messageLabel
 	}
 
+	chips := make([]string, len(m.recipients))
+	for i, r := range m.recipients {
+		chips[i] = chipStyle.Render(r.label())
+	}
+
+	var recipientBlock strings.Builder
+	recipientBlock.WriteString(recipientLabel + "\n")
+	if len(chips) > 0 {
+		recipientBlock.WriteString(strings.Join(chips, " ") + "\n")
+	}
+	recipientBlock.WriteString(m.recipientInput.View())
+	if m.focusIndex == 0 {
+		for i, suggestion := range m.suggestions {
+			if i == m.suggestionIndex {
+				recipientBlock.WriteString("\n" + selectedStyle.Render("  ▸ "+suggestion.label()))
+			} else {
+				recipientBlock.WriteString("\n" + helpStyle.Render("    "+suggestion.label()))
+			}
+		}
+	}
+
 	content := title + "\n\n"
 	content += style.Render(
-		recipientLabel + "\n" +
-			m.recipientInput.View() + "\n\n" +
+		recipientBlock.String() + "\n\n" +
 			messageLabel + "\n" +
 			m.messageInput.View(),
 	)
 
+	if m.sending {
+		content += fmt.Sprintf("\n\n  %s Creating group conversation...", m.spinner.View())
+	}
+
 	if m.err != nil {
 		content += "\n\n" + errorStyle.Render("Error: "+m.err.Error())
 	}
 
-	content += "\n\n" + helpStyle.Render("tab: switch field • enter: send • esc: back • q: quit")
+	removeLast := key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "remove last"))
+	bindings := []key.Binding{keys.AddRecipient, relabel(keys.NextField, "complete/switch field"), removeLast, relabel(keys.Submit, "send"), keys.Broadcast, keys.Back}
+	if m.broadcast {
+		bindings = []key.Binding{keys.AddRecipient, relabel(keys.NextField, "complete/switch field"), relabel(keys.Submit, "send to each"), keys.Personalise, relabel(keys.Broadcast, "group/single"), keys.Back}
+	}
+	content += "\n\n" + helpView(m.windowWidth, bindings...)
 
 	return content
 }
+
+// resolveRecipient resolves a typed handle or contact name. Contact names resolve to the
+// contact's first phone number, or first email if they have no phone number.
+func resolveRecipient(token string) (recipient, error) {
+	if looksLikeHandle(token) {
+		return recipient{name: resolver.Lookup(token), handle: token}, nil
+	}
+
+	contact := contacts.FindContactByName(token)
+	if contact == nil {
+		return recipient{}, fmt.Errorf("no contact named %q", token)
+	}
+
+	return contactRecipient(*contact)
+}
+
+// resolveGroupRecipients returns a recipient for every member of the named group, skipping
+// members with no phone number or email.
+func resolveGroupRecipients(name string) ([]recipient, error) {
+	group, err := contacts.FindGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("no group named @%s", name)
+	}
+
+	members, err := contacts.GroupMembers(*group)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipients []recipient
+	for _, member := range members {
+		if r, err := contactRecipient(member); err == nil {
+			recipients = append(recipients, r)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("group @%s has no members with a phone number or email", group.Name)
+	}
+	return recipients, nil
+}
+
+// contactRecipient picks the contact's first phone number, or first email if they have no
+// phone number.
+func contactRecipient(contact contacts.Contact) (recipient, error) {
+	if len(contact.PhoneNumbers) > 0 {
+		return recipient{name: contact.Name, handle: contact.PhoneNumbers[0]}, nil
+	}
+	if len(contact.Emails) > 0 {
+		return recipient{name: contact.Name, handle: contact.Emails[0]}, nil
+	}
+
+	return recipient{}, fmt.Errorf("contact %q has no phone number or email", contact.Name)
+}
+
+// looksLikeHandle reports whether s is an email address or phone number rather than a name.
+func looksLikeHandle(s string) bool {
+	if strings.Contains(s, "@") {
+		return true
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && !strings.ContainsRune("+-() .", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -19,16 +21,16 @@ type quickContactSavedMsg struct {
 }
 
 type QuickContactFormModel struct {
-	chat           models.Chat
-	identifier     string
-	nameInput      textinput.Model
-	err            error
-	windowWidth    int
-	windowHeight   int
-	showUnreadOnly bool
+	chat         models.Chat
+	identifier   string
+	nameInput    textinput.Model
+	err          error
+	windowWidth  int
+	windowHeight int
+	filters      conversationFilters
 }
 
-func NewQuickContactFormModel(chat models.Chat, identifier string, showUnreadOnly bool) QuickContactFormModel {
+func NewQuickContactFormModel(chat models.Chat, identifier string, filters conversationFilters) QuickContactFormModel {
 	nameInput := textinput.New()
 	nameInput.Placeholder = "Contact Name"
 	nameInput.Focus()
@@ -37,10 +39,10 @@ func NewQuickContactFormModel(chat models.Chat, identifier string, showUnreadOnl
 	nameInput.SetValue(chat.DisplayName)
 
 	return QuickContactFormModel{
-		chat:           chat,
-		identifier:     identifier,
-		nameInput:      nameInput,
-		showUnreadOnly: showUnreadOnly,
+		chat:       chat,
+		identifier: identifier,
+		nameInput:  nameInput,
+		filters:    filters,
 	}
 }
 
@@ -56,12 +58,12 @@ func (m QuickContactFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
+		if key.Matches(msg, keys.ForceQuit) {
 			return m, tea.Quit
 		}
 
-		if msg.String() == "esc" {
-			messagesModel := NewMessagesModel(m.chat, m.showUnreadOnly)
+		if key.Matches(msg, keys.Back) {
+			messagesModel := NewMessagesModel(m.chat, m.filters)
 			if m.windowWidth > 0 {
 				updatedModel, _ := messagesModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 				messagesModel = updatedModel.(MessagesModel)
@@ -69,13 +71,13 @@ func (m QuickContactFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return messagesModel, messagesModel.Init()
 		}
 
-		if msg.String() == "enter" || msg.String() == "ctrl+s" {
+		if key.Matches(msg, keys.Submit, keys.Save) {
 			return m, m.saveContact()
 		}
 
 	case quickContactSavedMsg:
 		if msg.success {
-			messagesModel := NewMessagesModel(msg.chat, m.showUnreadOnly)
+			messagesModel := NewMessagesModel(msg.chat, m.filters)
 			if m.windowWidth > 0 {
 				updatedModel, _ := messagesModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
 				messagesModel = updatedModel.(MessagesModel)
@@ -98,23 +100,13 @@ func (m QuickContactFormModel) saveContact() tea.Cmd {
 			return quickContactSavedMsg{success: false, err: fmt.Errorf("name is required"), chat: m.chat}
 		}
 
-		contact := contacts.Contact{
-			Name: name,
-		}
-
-		if strings.Contains(m.identifier, "@") {
-			contact.Emails = []string{m.identifier}
-		} else {
-			contact.PhoneNumbers = []string{m.identifier}
-		}
-
-		err := contacts.SaveContact(contact)
-		if err != nil {
+		if _, err := contacts.AddHandle(name, m.identifier); err != nil {
 			return quickContactSavedMsg{success: false, err: err, chat: m.chat}
 		}
 
 		updatedChats, err := imessage.GetChats()
-		if err != nil {
+		var blocklistErr *imessage.BlocklistError
+		if err != nil && !errors.As(err, &blocklistErr) {
 			return quickContactSavedMsg{success: false, err: err, chat: m.chat}
 		}
 
@@ -140,7 +132,7 @@ func (m QuickContactFormModel) View() string {
 
 	b.WriteString(normalStyle.Render(fmt.Sprintf("%s: %s", identifierType, m.identifier)) + "\n\n")
 
-	focusedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	focusedStyle := lipgloss.NewStyle().Foreground(accentColor)
 	b.WriteString(focusedStyle.Render("Name:") + "\n")
 	b.WriteString(m.nameInput.View() + "\n\n")
 
@@ -148,7 +140,7 @@ func (m QuickContactFormModel) View() string {
 		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n")
 	}
 
-	b.WriteString(helpStyle.Render("enter/ctrl+s: save • esc: cancel"))
+	b.WriteString(helpView(m.windowWidth, relabel(keys.Submit, "save"), keys.Save, relabel(keys.Back, "cancel")))
 
 	return b.String()
 }
@@ -2,43 +2,20 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
+// Styles shared by every screen. ApplyTheme sets them; they start with the dark theme.
 var (
-	titleStyle = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("213")).
-		MarginBottom(1)
-
-	selectedStyle = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("213"))
-
-	normalStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("255"))
-
-	helpStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("243")).
-		Italic(true)
-
-	errorStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")).
-		Bold(true)
-
-	statusStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("117"))
-
-	messageFromMeStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("111")).
-		Align(lipgloss.Right)
-
-	messageFromOtherStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("120"))
-
-	messageHeaderStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("255")).
-		Background(lipgloss.Color("240")).
-		Padding(0, 1)
-
-	inputStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("117")).
-		Bold(true)
+	titleStyle            lipgloss.Style
+	selectedStyle         lipgloss.Style
+	normalStyle           lipgloss.Style
+	helpStyle             lipgloss.Style
+	errorStyle            lipgloss.Style
+	statusStyle           lipgloss.Style
+	messageFromMeStyle    lipgloss.Style
+	messageFromOtherStyle lipgloss.Style
+	messageHeaderStyle    lipgloss.Style
+	inputStyle            lipgloss.Style
 )
+
+func init() {
+	ApplyTheme(builtinThemes["dark"])
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/saravenpi/chime/internal/templates"
+)
+
+type templateItem struct {
+	template templates.Template
+}
+
+func (i templateItem) FilterValue() string { return i.template.Name }
+func (i templateItem) Title() string       { return i.template.Name }
+func (i templateItem) Description() string {
+	preview := strings.ReplaceAll(i.template.Body, "\n", " ")
+	if runes := []rune(preview); len(runes) > 60 {
+		preview = string(runes[:57]) + "..."
+	}
+	return preview
+}
+
+type templatesLoadedMsg struct {
+	templates []templates.Template
+	err       error
+}
+
+func loadTemplatesCmd() tea.Cmd {
+	return func() tea.Msg {
+		loaded, err := templates.ListTemplates()
+		return templatesLoadedMsg{templates: loaded, err: err}
+	}
+}
+
+// newTemplatePicker creates the list used to choose a template while composing.
+func newTemplatePicker(width, height int) list.Model {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(accentColor).
+		Bold(true)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(dimColor)
+
+	l := list.New([]list.Item{}, delegate, width, height)
+	l.Title = "Templates"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(true)
+	l.SetShowHelp(false)
+	l.KeyMap = listKeyMap()
+	return l
+}
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/saravenpi/chime/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Theme is a colour palette. Colours are ANSI numbers ("5", "213") or hex ("#ff87ff");
+// an empty colour leaves the terminal's default.
+type Theme struct {
+	// Extends names the theme a theme file starts from; colours it sets replace the base's.
+	Extends          string `yaml:"extends,omitempty"`
+	Title            string `yaml:"title"`
+	Selected         string `yaml:"selected"`
+	Text             string `yaml:"text"`
+	Help             string `yaml:"help"`
+	Error            string `yaml:"error"`
+	Status           string `yaml:"status"`
+	FromMe           string `yaml:"from_me"`
+	FromOther        string `yaml:"from_other"`
+	Header           string `yaml:"header"`
+	HeaderBackground string `yaml:"header_background"`
+	Input            string `yaml:"input"`
+	// Accent marks focused fields, list selections, borders and recipient chips.
+	Accent string `yaml:"accent"`
+	// AccentText is text drawn on the accent colour.
+	AccentText string `yaml:"accent_text"`
+	// Dim marks unfocused labels and secondary text.
+	Dim string `yaml:"dim"`
+}
+
+// Built-in themes. "auto" picks dark or light from the terminal background; "mono" uses no
+// colour at all and is used whenever NO_COLOR is set.
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Title: "213", Selected: "213", Text: "255", Help: "243", Error: "196", Status: "117",
+		FromMe: "111", FromOther: "120", Header: "255", HeaderBackground: "240", Input: "117",
+		Accent: "5", AccentText: "255", Dim: "8",
+	},
+	"light": {
+		Title: "127", Selected: "127", Text: "235", Help: "242", Error: "160", Status: "25",
+		FromMe: "25", FromOther: "28", Header: "232", HeaderBackground: "252", Input: "25",
+		Accent: "127", AccentText: "255", Dim: "245",
+	},
+	"high-contrast": {
+		Title: "15", Selected: "11", Text: "15", Help: "7", Error: "9", Status: "14",
+		FromMe: "14", FromOther: "10", Header: "0", HeaderBackground: "15", Input: "11",
+		Accent: "11", AccentText: "0", Dim: "7",
+	},
+	"mono": {},
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|#[0-9a-fA-F]{3}|[0-9]{1,3})$`)
+
+var (
+	accentColor     lipgloss.TerminalColor
+	accentTextColor lipgloss.TerminalColor
+	dimColor        lipgloss.TerminalColor
+	monochrome      bool
+)
+
+// GetThemesDir returns the directory user themes are read from (~/.chime/themes).
+func GetThemesDir() string {
+	return filepath.Join(config.DataDir(), "themes")
+}
+
+// ThemeNames returns the built-in themes and the themes in the themes directory. Files named
+// after a built-in theme or "auto" are left out, since the built-in name always wins.
+func ThemeNames() []string {
+	names := []string{"auto"}
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	entries, _ := os.ReadDir(GetThemesDir())
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".yml"); ok && !entry.IsDir() {
+			if _, builtin := builtinThemes[name]; !builtin && name != "auto" {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// LoadTheme returns the named theme: "auto", a built-in theme, or <name>.yml in the themes
+// directory. NO_COLOR selects the monochrome theme whatever the name.
+func LoadTheme(name string) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return builtinThemes["mono"], nil
+	}
+	return loadTheme(name, 0)
+}
+
+func loadTheme(name string, depth int) (Theme, error) {
+	if name == "" || name == "auto" {
+		if lipgloss.HasDarkBackground() {
+			name = "dark"
+		} else {
+			name = "light"
+		}
+	}
+	if theme, ok := builtinThemes[name]; ok {
+		return theme, nil
+	}
+	if depth > 4 {
+		return Theme{}, fmt.Errorf("theme %q extends too many themes", name)
+	}
+
+	path := filepath.Join(GetThemesDir(), name+".yml")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	if err != nil {
+		return Theme{}, fmt.Errorf("failed to read theme: %w", err)
+	}
+
+	var theme Theme
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&theme); err != nil && !errors.Is(err, io.EOF) {
+		return Theme{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := theme.validate(); err != nil {
+		return Theme{}, fmt.Errorf("invalid theme %s: %w", path, err)
+	}
+
+	base := builtinThemes["dark"]
+	if theme.Extends != "" {
+		if base, err = loadTheme(theme.Extends, depth+1); err != nil {
+			return Theme{}, err
+		}
+	}
+	return base.merge(theme), nil
+}
+
+// colors returns pointers to every colour in the theme, keyed by YAML name.
+func (t *Theme) colors() map[string]*string {
+	return map[string]*string{
+		"title": &t.Title, "selected": &t.Selected, "text": &t.Text, "help": &t.Help,
+		"error": &t.Error, "status": &t.Status, "from_me": &t.FromMe, "from_other": &t.FromOther,
+		"header": &t.Header, "header_background": &t.HeaderBackground, "input": &t.Input,
+		"accent": &t.Accent, "accent_text": &t.AccentText, "dim": &t.Dim,
+	}
+}
+
+func (t Theme) validate() error {
+	for name, color := range t.colors() {
+		if *color != "" && !colorPattern.MatchString(*color) {
+			return fmt.Errorf("%s: %q is not an ANSI colour number or #rrggbb", name, *color)
+		}
+	}
+	return nil
+}
+
+// merge returns t with every colour set in override replacing its own.
+func (t Theme) merge(override Theme) Theme {
+	colors := t.colors()
+	for name, color := range override.colors() {
+		if *color != "" {
+			*colors[name] = *color
+		}
+	}
+	t.Extends = ""
+	return t
+}
+
+func themeColor(color string) lipgloss.TerminalColor {
+	if color == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(color)
+}
+
+// ApplyTheme restyles the whole UI with t. A theme without colours switches to
+// monochrome output, where selections are shown with bold and reverse video instead.
+func ApplyTheme(t Theme) {
+	monochrome = t == builtinThemes["mono"]
+	if monochrome {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	accentColor = themeColor(t.Accent)
+	accentTextColor = themeColor(t.AccentText)
+	dimColor = themeColor(t.Dim)
+
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(themeColor(t.Title)).
+		MarginBottom(1)
+
+	selectedStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(themeColor(t.Selected))
+
+	normalStyle = lipgloss.NewStyle().
+		Foreground(themeColor(t.Text))
+
+	helpStyle = lipgloss.NewStyle().
+		Foreground(themeColor(t.Help)).
+		Italic(true)
+
+	errorStyle = lipgloss.NewStyle().
+		Foreground(themeColor(t.Error)).
+		Bold(true)
+
+	statusStyle = lipgloss.NewStyle().
+		Foreground(themeColor(t.Status))
+
+	messageFromMeStyle = lipgloss.NewStyle().
+		Foreground(themeColor(t.FromMe)).
+		Align(lipgloss.Right)
+
+	messageFromOtherStyle = lipgloss.NewStyle().
+		Foreground(themeColor(t.FromOther))
+
+	messageHeaderStyle = lipgloss.NewStyle().
+		Foreground(themeColor(t.Header)).
+		Background(themeColor(t.HeaderBackground)).
+		Padding(0, 1)
+
+	inputStyle = lipgloss.NewStyle().
+		Foreground(themeColor(t.Input)).
+		Bold(true)
+
+	if monochrome {
+		selectedStyle = selectedStyle.Reverse(true)
+		messageHeaderStyle = messageHeaderStyle.Reverse(true)
+		errorStyle = errorStyle.Underline(true)
+	}
+}
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/saravenpi/chime/internal/config"
+)
+
+// writeThemes points the data directory at a temporary one holding the given theme files,
+// keyed by theme name, for the rest of the test.
+func writeThemes(t *testing.T, themes map[string]string) {
+	t.Helper()
+	previous := config.Current()
+	t.Cleanup(func() { config.Set(previous) })
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	config.Set(cfg)
+	t.Setenv("NO_COLOR", "")
+
+	if err := os.MkdirAll(GetThemesDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range themes {
+		if err := os.WriteFile(filepath.Join(GetThemesDir(), name+".yml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadThemeExtends(t *testing.T) {
+	writeThemes(t, map[string]string{
+		"base":  "extends: light\naccent: \"#ff87ff\"\n",
+		"child": "extends: base\ntitle: \"42\"\n",
+		"plain": "error: \"#f00\"\n",
+	})
+
+	child, err := LoadTheme("child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := builtinThemes["light"]
+	want.Accent = "#ff87ff"
+	want.Title = "42"
+	if child != want {
+		t.Errorf("LoadTheme(child) =\n%#v\nwant\n%#v", child, want)
+	}
+
+	plain, err := LoadTheme("plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = builtinThemes["dark"]
+	want.Error = "#f00"
+	if plain != want {
+		t.Errorf("LoadTheme(plain) =\n%#v\nwant dark with a red error colour\n%#v", plain, want)
+	}
+
+	if dark, err := LoadTheme("dark"); err != nil || dark != builtinThemes["dark"] {
+		t.Errorf("LoadTheme(dark) = %#v, %v, want the built-in theme", dark, err)
+	}
+}
+
+func TestLoadThemeErrors(t *testing.T) {
+	writeThemes(t, map[string]string{
+		"loop-a":    "extends: loop-b\n",
+		"loop-b":    "extends: loop-a\n",
+		"chain-1":   "extends: chain-2\n",
+		"chain-2":   "extends: chain-3\n",
+		"chain-3":   "extends: chain-4\n",
+		"chain-4":   "extends: chain-5\n",
+		"chain-5":   "extends: chain-6\n",
+		"chain-6":   "extends: light\n",
+		"named":     "accent: purple\n",
+		"short-hex": "title: \"#12345\"\n",
+		"too-long":  "dim: \"1234\"\n",
+		"unknown":   "accent: \"5\"\nbackground: \"0\"\n",
+		"missing":   "extends: nowhere\n",
+	})
+
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{"loop-a", "extends too many themes"},
+		{"chain-1", "extends too many themes"},
+		{"named", `accent: "purple" is not an ANSI colour number or #rrggbb`},
+		{"short-hex", `title: "#12345"`},
+		{"too-long", `dim: "1234"`},
+		{"unknown", "field background not found"},
+		{"missing", `unknown theme "nowhere"`},
+		{"nope", `unknown theme "nope"`},
+	}
+	for _, tt := range tests {
+		_, err := LoadTheme(tt.name)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("LoadTheme(%q) = %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// A chain of five theme files is allowed.
+	if _, err := LoadTheme("chain-2"); err != nil {
+		t.Errorf("LoadTheme(chain-2) = %v, want nil", err)
+	}
+}
+
+func TestLoadThemeNoColor(t *testing.T) {
+	writeThemes(t, map[string]string{"custom": "accent: \"5\"\n"})
+	t.Setenv("NO_COLOR", "1")
+
+	for _, name := range []string{"dark", "high-contrast", "custom", "nope"} {
+		theme, err := LoadTheme(name)
+		if err != nil {
+			t.Errorf("LoadTheme(%q) with NO_COLOR = %v, want nil", name, err)
+		}
+		if theme != builtinThemes["mono"] {
+			t.Errorf("LoadTheme(%q) with NO_COLOR = %#v, want mono", name, theme)
+		}
+	}
+}
+
+func TestThemeNames(t *testing.T) {
+	writeThemes(t, map[string]string{
+		"solarized": "extends: light\n",
+		"auto":      "accent: \"5\"\n",
+		"dark":      "accent: \"5\"\n",
+	})
+
+	want := []string{"auto", "dark", "high-contrast", "light", "mono", "solarized"}
+	if got := ThemeNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ThemeNames() = %q, want %q", got, want)
+	}
+}
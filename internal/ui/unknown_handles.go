@@ -0,0 +1,537 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/saravenpi/chime/internal/contacts"
+	"github.com/saravenpi/chime/internal/imessage"
+	"github.com/saravenpi/chime/internal/models"
+	"github.com/saravenpi/chime/internal/resolver"
+)
+
+const (
+	previewMessageCount = 5
+	previewHeight       = previewMessageCount + 3
+)
+
+type unknownHandleItem struct {
+	activity models.HandleActivity
+	selected bool
+	ignored  bool
+}
+
+func (i unknownHandleItem) FilterValue() string { return i.activity.Handle }
+func (i unknownHandleItem) Title() string {
+	check := "☐ "
+	if i.selected {
+		check = "☑ "
+	}
+	return check + i.activity.Handle
+}
+func (i unknownHandleItem) Description() string {
+	desc := fmt.Sprintf("%d messages • %s", i.activity.MessageCount, formatTimeAgo(i.activity.LastTime))
+	if i.ignored {
+		desc += " • ignored"
+	}
+	return desc
+}
+
+type unknownHandlesLoadedMsg struct {
+	handles []models.HandleActivity
+	ignored map[string]bool
+	err     error
+}
+
+type handlePreviewMsg struct {
+	handle   string
+	messages []models.Message
+	err      error
+}
+
+type handleAddedMsg struct {
+	handle  string
+	contact contacts.Contact
+	err     error
+}
+
+type handlesIgnoredMsg struct {
+	count  int
+	ignore bool
+	err    error
+}
+
+type UnknownHandlesModel struct {
+	list         list.Model
+	handles      []models.HandleActivity
+	ignored      map[string]bool
+	selected     map[string]bool
+	showIgnored  bool
+	previews     map[string][]models.Message
+	previewErrs  map[string]error
+	loading      bool
+	err          error
+	status       string
+	adding       bool
+	addQueue     []string
+	added        int
+	nameInput    textinput.Model
+	windowWidth  int
+	windowHeight int
+	showHelp     bool
+}
+
+// NewUnknownHandlesModel creates a view of every handle in chat.db that no contact source knows.
+func NewUnknownHandlesModel() UnknownHandlesModel {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(accentColor).
+		Bold(true)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(dimColor)
+
+	l := list.New([]list.Item{}, delegate, 80, 20-previewHeight)
+	l.Title = "Unknown Senders"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(true)
+	l.SetShowHelp(false)
+	l.KeyMap = listKeyMap()
+
+	nameInput := textinput.New()
+	nameInput.Placeholder = "Contact name (an existing name adds the handle to that contact)"
+	nameInput.CharLimit = 100
+	nameInput.Width = 60
+
+	return UnknownHandlesModel{
+		list:         l,
+		selected:     make(map[string]bool),
+		ignored:      make(map[string]bool),
+		previews:     make(map[string][]models.Message),
+		previewErrs:  make(map[string]error),
+		nameInput:    nameInput,
+		loading:      true,
+		windowWidth:  80,
+		windowHeight: 30,
+	}
+}
+
+func (m UnknownHandlesModel) Init() tea.Cmd {
+	return m.loadHandlesCmd()
+}
+
+func (m UnknownHandlesModel) loadHandlesCmd() tea.Cmd {
+	return func() tea.Msg {
+		activity, err := imessage.GetHandleActivity()
+		if err != nil {
+			return unknownHandlesLoadedMsg{err: err}
+		}
+
+		ignored, err := contacts.ListIgnoredHandles()
+		if err != nil {
+			return unknownHandlesLoadedMsg{err: err}
+		}
+
+		blocklist, err := contacts.LoadBlocklist()
+		if err != nil {
+			return unknownHandlesLoadedMsg{err: err}
+		}
+
+		var unknown []models.HandleActivity
+		for _, a := range activity {
+			if resolver.Lookup(a.Handle) == "" && !blocklist.Blocks(a.Handle) {
+				unknown = append(unknown, a)
+			}
+		}
+		return unknownHandlesLoadedMsg{handles: unknown, ignored: ignored}
+	}
+}
+
+func (m UnknownHandlesModel) loadPreviewCmd(handle string) tea.Cmd {
+	return func() tea.Msg {
+		messages, err := imessage.GetRecentMessagesForHandle(handle, previewMessageCount)
+		return handlePreviewMsg{handle: handle, messages: messages, err: err}
+	}
+}
+
+func (m UnknownHandlesModel) addHandleCmd(handle, name string) tea.Cmd {
+	return func() tea.Msg {
+		contact, err := contacts.AddHandle(name, handle)
+		return handleAddedMsg{handle: handle, contact: contact, err: err}
+	}
+}
+
+func (m UnknownHandlesModel) ignoreHandlesCmd(handles []string, ignore bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if ignore {
+			err = contacts.IgnoreHandles(handles...)
+		} else {
+			err = contacts.UnignoreHandles(handles...)
+		}
+		return handlesIgnoredMsg{count: len(handles), ignore: ignore, err: err}
+	}
+}
+
+// targetHandles returns the selected handles in list order, or the highlighted handle if none are selected.
+func (m UnknownHandlesModel) targetHandles() []string {
+	var handles []string
+	for _, item := range m.list.Items() {
+		if h := item.(unknownHandleItem); h.selected {
+			handles = append(handles, h.activity.Handle)
+		}
+	}
+	if len(handles) == 0 {
+		if item, ok := m.list.SelectedItem().(unknownHandleItem); ok {
+			handles = append(handles, item.activity.Handle)
+		}
+	}
+	return handles
+}
+
+func (m *UnknownHandlesModel) refreshItems() {
+	var items []list.Item
+	for _, a := range m.handles {
+		ignored := m.ignored[contacts.NormalizeIdentifier(a.Handle)]
+		if ignored && !m.showIgnored {
+			continue
+		}
+		items = append(items, unknownHandleItem{activity: a, selected: m.selected[a.Handle], ignored: ignored})
+	}
+	m.list.SetItems(items)
+
+	title := fmt.Sprintf("Unknown Senders - %d handles", len(items))
+	if len(m.selected) > 0 {
+		title += fmt.Sprintf(" (%d selected)", len(m.selected))
+	}
+	m.list.Title = title
+}
+
+// previewCmd loads recent messages for the highlighted handle if they haven't been loaded yet.
+func (m *UnknownHandlesModel) previewCmd() tea.Cmd {
+	item, ok := m.list.SelectedItem().(unknownHandleItem)
+	if !ok {
+		return nil
+	}
+	if _, loaded := m.previews[item.activity.Handle]; loaded {
+		return nil
+	}
+	m.previews[item.activity.Handle] = nil
+	return m.loadPreviewCmd(item.activity.Handle)
+}
+
+// nextAdd prompts for the next handle in the add queue, or ends the add flow when it is empty.
+func (m *UnknownHandlesModel) nextAdd() tea.Cmd {
+	if len(m.addQueue) == 0 {
+		m.adding = false
+		m.nameInput.Blur()
+		if m.added > 0 {
+			m.status = fmt.Sprintf("Added %d handle(s) to contacts", m.added)
+		}
+		m.loading = true
+		return m.loadHandlesCmd()
+	}
+
+	m.nameInput.SetValue("")
+	m.nameInput.Focus()
+	handle := m.addQueue[0]
+	if _, loaded := m.previews[handle]; !loaded {
+		m.previews[handle] = nil
+		return tea.Batch(textinput.Blink, m.loadPreviewCmd(handle))
+	}
+	return textinput.Blink
+}
+
+func (m UnknownHandlesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.windowWidth = msg.Width
+		m.windowHeight = msg.Height
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 4 - previewHeight)
+		return m, nil
+
+	case unknownHandlesLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+
+		m.err = nil
+		m.handles = msg.handles
+		m.ignored = msg.ignored
+		known := make(map[string]bool)
+		for _, a := range m.handles {
+			known[a.Handle] = true
+		}
+		for handle := range m.selected {
+			if !known[handle] {
+				delete(m.selected, handle)
+			}
+		}
+		m.refreshItems()
+		return m, m.previewCmd()
+
+	case handlePreviewMsg:
+		if msg.err != nil {
+			m.previewErrs[msg.handle] = msg.err
+			return m, nil
+		}
+		delete(m.previewErrs, msg.handle)
+		m.previews[msg.handle] = append([]models.Message{}, msg.messages...)
+		return m, nil
+
+	case handleAddedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.err = nil
+		m.added++
+		delete(m.selected, msg.handle)
+		m.addQueue = m.addQueue[1:]
+		return m, m.nextAdd()
+
+	case handlesIgnoredMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		if msg.ignore {
+			m.status = fmt.Sprintf("Ignored %d handle(s)", msg.count)
+		} else {
+			m.status = fmt.Sprintf("Stopped ignoring %d handle(s)", msg.count)
+		}
+		m.selected = make(map[string]bool)
+		m.loading = true
+		return m, m.loadHandlesCmd()
+
+	case ContactsChangedMsg:
+		if m.adding {
+			return m, nil
+		}
+		return m, m.loadHandlesCmd()
+
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.ForceQuit) {
+			return m, tea.Quit
+		}
+
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		if m.adding {
+			return m.updateAdding(msg)
+		}
+
+		if m.list.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			return m, tea.Batch(cmd, m.previewCmd())
+		}
+
+		switch {
+		case key.Matches(msg, keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, keys.Back, keys.Quit):
+			if m.list.FilterState() == list.FilterApplied {
+				m.list.ResetFilter()
+				return m, nil
+			}
+			menuModel := NewMenuModel()
+			if m.windowWidth > 0 {
+				updatedModel, _ := menuModel.Update(tea.WindowSizeMsg{Width: m.windowWidth, Height: m.windowHeight})
+				menuModel = updatedModel.(MenuModel)
+			}
+			return menuModel, menuModel.Init()
+
+		case key.Matches(msg, keys.Refresh):
+			m.loading = true
+			m.status = ""
+			for handle := range m.previewErrs {
+				delete(m.previews, handle)
+				delete(m.previewErrs, handle)
+			}
+			return m, m.loadHandlesCmd()
+
+		case key.Matches(msg, keys.Select):
+			if item, ok := m.list.SelectedItem().(unknownHandleItem); ok {
+				if m.selected[item.activity.Handle] {
+					delete(m.selected, item.activity.Handle)
+				} else {
+					m.selected[item.activity.Handle] = true
+				}
+				index := m.list.Index()
+				m.refreshItems()
+				m.list.Select(index + 1)
+			}
+			return m, m.previewCmd()
+
+		case key.Matches(msg, keys.SelectAll):
+			if len(m.selected) == len(m.list.Items()) {
+				m.selected = make(map[string]bool)
+			} else {
+				for _, item := range m.list.Items() {
+					m.selected[item.(unknownHandleItem).activity.Handle] = true
+				}
+			}
+			m.refreshItems()
+			return m, nil
+
+		case key.Matches(msg, keys.AddContact, keys.Open):
+			m.addQueue = m.targetHandles()
+			if len(m.addQueue) == 0 {
+				return m, nil
+			}
+			m.adding = true
+			m.added = 0
+			m.status = ""
+			m.err = nil
+			return m, m.nextAdd()
+
+		case key.Matches(msg, keys.Ignore):
+			handles := m.targetHandles()
+			if len(handles) == 0 {
+				return m, nil
+			}
+			ignore := !m.ignored[contacts.NormalizeIdentifier(handles[0])]
+			return m, m.ignoreHandlesCmd(handles, ignore)
+
+		case key.Matches(msg, keys.ShowIgnored):
+			m.showIgnored = !m.showIgnored
+			m.refreshItems()
+			return m, m.previewCmd()
+		}
+
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, tea.Batch(cmd, m.previewCmd())
+	}
+
+	return m, nil
+}
+
+func (m UnknownHandlesModel) updateAdding(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back):
+		m.addQueue = nil
+		return m, m.nextAdd()
+
+	case key.Matches(msg, keys.Skip):
+		m.addQueue = m.addQueue[1:]
+		return m, m.nextAdd()
+
+	case key.Matches(msg, keys.Submit):
+		name := strings.TrimSpace(m.nameInput.Value())
+		if name == "" {
+			m.err = fmt.Errorf("enter a name, or press %s to skip this handle", keys.Skip.Help().Key)
+			return m, nil
+		}
+		return m, m.addHandleCmd(m.addQueue[0], name)
+	}
+
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
+func (m UnknownHandlesModel) renderPreview(handle string) string {
+	var b strings.Builder
+	b.WriteString(normalStyle.Render("Recent messages:") + "\n")
+
+	messages, loaded := m.previews[handle]
+	switch {
+	case m.previewErrs[handle] != nil:
+		b.WriteString(errorStyle.Render(fmt.Sprintf("  Couldn't load messages: %v (%s to retry)", m.previewErrs[handle], keys.Refresh.Help().Key)) + "\n")
+	case !loaded || messages == nil:
+		b.WriteString(helpStyle.Render("  Loading...") + "\n")
+	case len(messages) == 0:
+		b.WriteString(helpStyle.Render("  No messages") + "\n")
+	}
+
+	width := m.windowWidth - 12
+	if width < 20 {
+		width = 20
+	}
+	for _, message := range messages {
+		sender := "Them"
+		style := messageFromOtherStyle
+		if message.IsFromMe {
+			sender = "You"
+			style = messageFromMeStyle
+		}
+		text := strings.ReplaceAll(message.Text, "\n", " ")
+		if text == "" {
+			text = "[attachment]"
+		}
+		if len([]rune(text)) > width {
+			text = string([]rune(text)[:width-3]) + "..."
+		}
+		b.WriteString(fmt.Sprintf("  %s %s\n", style.Render(sender+":"), text))
+	}
+	return b.String()
+}
+
+func (m UnknownHandlesModel) View() string {
+	if m.showHelp {
+		return helpOverlay("Unknown Senders", m.windowWidth,
+			[]key.Binding{keys.Up, keys.Down, keys.Search, keys.Refresh},
+			[]key.Binding{keys.Select, keys.SelectAll, relabel(keys.AddContact, "add to contacts"), relabel(keys.Open, "add to contacts")},
+			[]key.Binding{keys.Ignore, keys.ShowIgnored},
+			[]key.Binding{keys.Back, keys.Help},
+		)
+	}
+
+	if m.adding && len(m.addQueue) > 0 {
+		handle := m.addQueue[0]
+		s := titleStyle.Render(fmt.Sprintf("Add Contact (%d left)", len(m.addQueue))) + "\n\n"
+		s += normalStyle.Render("Handle: "+handle) + "\n\n"
+		s += m.renderPreview(handle) + "\n"
+		s += normalStyle.Render("Name:") + "\n"
+		s += m.nameInput.View() + "\n\n"
+		if m.err != nil {
+			s += errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n"
+		}
+		s += helpView(m.windowWidth, relabel(keys.Submit, "save"), keys.Skip, relabel(keys.Back, "stop adding"))
+		return s
+	}
+
+	if m.loading {
+		return "\n  Looking for unknown senders...\n"
+	}
+
+	if m.err != nil {
+		s := titleStyle.Render("Unknown Senders") + "\n\n"
+		s += errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n"
+		s += helpView(m.windowWidth, relabel(keys.Refresh, "retry"), keys.Back)
+		return s
+	}
+
+	if len(m.list.Items()) == 0 {
+		s := titleStyle.Render("Unknown Senders") + "\n\n"
+		if m.status != "" {
+			s += statusStyle.Render("  "+m.status) + "\n\n"
+		}
+		s += normalStyle.Render("  Everyone you've messaged is in your contacts.") + "\n\n"
+		s += helpView(m.windowWidth, keys.ShowIgnored, keys.Refresh, keys.Back)
+		return s
+	}
+
+	s := m.list.View() + "\n"
+	if item, ok := m.list.SelectedItem().(unknownHandleItem); ok {
+		s += m.renderPreview(item.activity.Handle)
+	}
+	if m.status != "" {
+		s += statusStyle.Render(m.status) + "\n"
+	}
+	s += helpView(m.windowWidth, keys.Help, keys.Select, keys.SelectAll, relabel(keys.AddContact, "add to contacts"), keys.Ignore, keys.ShowIgnored, keys.Search, keys.Refresh, keys.Back)
+	return s
+}
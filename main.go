@@ -3,14 +3,27 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/saravenpi/chime/internal/config"
+	"github.com/saravenpi/chime/internal/contacts"
+	"github.com/saravenpi/chime/internal/resolver"
 	"github.com/saravenpi/chime/internal/ui"
 )
 
 const version = "1.0.0"
 
 func main() {
+	cfg, configPath, err := config.Load()
+	if err == nil {
+		if err = applyConfig(cfg); err != nil {
+			err = fmt.Errorf("invalid config %s: %w", configPath, err)
+		}
+	}
+
+	// These commands work with a broken config, so it can be inspected and fixed.
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "version", "-v", "--version":
@@ -19,6 +32,34 @@ func main() {
 		case "help", "-h", "--help":
 			printHelp()
 			return
+		case "config":
+			if err := runConfigCommand(os.Args[2:], configPath, err); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "contacts":
+			if err := runContactsCommand(os.Args[2:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "blocklist":
+			if err := runBlocklistCommand(os.Args[2:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
 		default:
 			fmt.Printf("Unknown command: %s\n", os.Args[1])
 			printHelp()
@@ -26,14 +67,310 @@ func main() {
 		}
 	}
 
-	initialModel := ui.NewMenuModel()
+	initialModel := ui.NewStartModel(cfg.DefaultView)
 	p := tea.NewProgram(initialModel, tea.WithAltScreen())
+
+	stopWatching, err := contacts.WatchContacts(func() {
+		p.Send(ui.ContactsChangedMsg{})
+	})
+	if err == nil {
+		defer stopWatching()
+	}
+
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// applyConfig puts cfg into effect: the data directory, contacts backend, phone region,
+// name lookup order, send delay, key bindings and theme.
+func applyConfig(cfg config.Config) error {
+	config.Set(cfg)
+
+	if err := contacts.SetBackend(cfg.ContactsBackend); err != nil {
+		return fmt.Errorf("contacts_backend (or CHIME_CONTACTS_BACKEND): %w", err)
+	}
+	if cfg.Region != "" {
+		if err := contacts.SetDefaultRegion(cfg.Region); err != nil {
+			return fmt.Errorf("region: %w", err)
+		}
+	}
+	if err := resolver.SetOrder(cfg.NameSources); err != nil {
+		return fmt.Errorf("name_sources (or CHIME_NAME_SOURCES): %w", err)
+	}
+	ui.UndoSendDelay = cfg.Send.UndoDelay
+	if err := ui.ApplyKeyBindings(cfg.Keys); err != nil {
+		return fmt.Errorf("keys: %w", err)
+	}
+	theme, err := ui.LoadTheme(cfg.Theme)
+	if err != nil {
+		return fmt.Errorf("theme: %w", err)
+	}
+	ui.ApplyTheme(theme)
+	return nil
+}
+
+// runConfigCommand handles `chime config [path]`, printing the effective settings with
+// automatic values resolved, or where they are read from. loadErr is the error, if any,
+// from loading and applying the config; it is reported instead of the settings.
+func runConfigCommand(args []string, path string, loadErr error) error {
+	if len(args) > 0 && args[0] == "path" {
+		fmt.Println(path)
+		return nil
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("unknown config subcommand: %s", args[0])
+	}
+	if loadErr != nil {
+		return loadErr
+	}
+
+	cfg := config.Current()
+	cfg.Region = contacts.DefaultRegion()
+	cfg.ContactsBackend = contacts.Backend()
+	cfg.NameSources = resolver.Order()
+
+	data, err := cfg.Marshal()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		fmt.Printf("# %s not found; showing defaults\n", path)
+	} else {
+		fmt.Printf("# Loaded from %s\n", path)
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+// runContactsCommand handles `chime contacts <subcommand>`.
+func runContactsCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing contacts subcommand (see chime help)")
+	}
+
+	switch args[0] {
+	case "import-addressbook":
+		imported, skipped, err := contacts.ImportAddressBook()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d contacts from the macOS AddressBook (%d already present)\n", imported, skipped)
+		return nil
+	case "import":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: chime contacts import <file.vcf|file.csv>")
+		}
+		if strings.EqualFold(filepath.Ext(args[len(args)-1]), ".csv") {
+			return importCSV(args[1:])
+		}
+		imported, skipped, warnings, err := contacts.ImportVCardFile(args[1])
+		for _, warning := range warnings {
+			fmt.Printf("  ! %s\n", warning)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d contacts from %s (%d already present)\n", imported, args[1], skipped)
+		return nil
+	case "export":
+		return exportContacts(args[1:])
+	case "migrate":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: chime contacts migrate <yaml|sqlite>")
+		}
+		from, to := contacts.Backend(), strings.ToLower(args[1])
+		migrated, err := contacts.MigrateContacts(from, to)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Migrated %d contacts from %s to %s\n", migrated, from, to)
+		if env := os.Getenv("CHIME_CONTACTS_BACKEND"); env != "" && !strings.EqualFold(env, to) {
+			fmt.Printf("Note: CHIME_CONTACTS_BACKEND is set to %s; unset it to use %s\n", env, to)
+		} else if set := config.Current().ContactsBackend; set != "" && !strings.EqualFold(set, to) {
+			fmt.Printf("Note: contacts_backend is set to %s in %s; change it to use %s\n", set, config.Path(), to)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown contacts subcommand: %s", args[0])
+	}
+}
+
+// importCSV handles `chime contacts import [--preset name] [--map Header=field]... [--dry-run] <file.csv>`.
+// Contacts matching an existing one are merged into it; --dry-run only prints the plan.
+func importCSV(args []string) error {
+	preset := ""
+	dryRun := false
+	var specs []string
+	for i := 0; i < len(args)-1; i++ {
+		switch args[i] {
+		case "--dry-run", "-n":
+			dryRun = true
+		case "--preset", "--map":
+			if i+1 >= len(args)-1 {
+				return fmt.Errorf("%s requires a value", args[i])
+			}
+			if args[i] == "--preset" {
+				preset = strings.ToLower(args[i+1])
+			} else {
+				specs = append(specs, args[i+1])
+			}
+			i++
+		default:
+			return fmt.Errorf("unknown import option: %s", args[i])
+		}
+	}
+	path := args[len(args)-1]
+
+	mapping, err := contacts.ParseCSVMapping(specs)
+	if err != nil {
+		return err
+	}
+	parsed, warnings, usedPreset, err := contacts.ReadCSVFile(path, preset, mapping)
+	if err != nil {
+		return err
+	}
+	plan, err := contacts.PlanImport(parsed)
+	if err != nil {
+		return err
+	}
+
+	if usedPreset != "" {
+		fmt.Printf("Reading %s with the %s preset\n", path, usedPreset)
+	}
+	for _, warning := range append(warnings, plan.Warnings...) {
+		fmt.Printf("  ! %s\n", warning)
+	}
+
+	if dryRun {
+		for _, change := range plan.Changes {
+			if change.Existing == nil {
+				identifiers := append(append([]string{}, change.Contact.PhoneNumbers...), change.Contact.Emails...)
+				fmt.Printf("  + %s (%s)\n", change.Contact.Name, strings.Join(identifiers, ", "))
+			} else {
+				fmt.Printf("  ~ %s: add %s\n", change.Existing.Name, contacts.ImportSummary(*change.Existing, change.Contact))
+			}
+		}
+		fmt.Printf("Dry run: would create %d contacts and update %d (%d unchanged)\n", plan.Created(), plan.Merged(), plan.Unchanged)
+		return nil
+	}
+
+	if err := contacts.ApplyImportPlan(plan); err != nil {
+		return err
+	}
+	fmt.Printf("Created %d contacts and updated %d from %s (%d unchanged)\n", plan.Created(), plan.Merged(), path, plan.Unchanged)
+	return nil
+}
+
+// runBlocklistCommand handles `chime blocklist [list|add|remove]`.
+func runBlocklistCommand(args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		blocklist, err := contacts.LoadBlocklist()
+		if err != nil {
+			return err
+		}
+		if len(blocklist.Entries) == 0 {
+			fmt.Println("The blocklist is empty")
+			return nil
+		}
+		for _, entry := range blocklist.Entries {
+			fmt.Println(entry)
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: chime blocklist add <handle|pattern>...")
+		}
+		if err := contacts.Block(args[1:]...); err != nil {
+			return err
+		}
+		fmt.Printf("Blocked %s\n", strings.Join(args[1:], ", "))
+		return nil
+	case "remove":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: chime blocklist remove <handle|pattern>...")
+		}
+		for _, handle := range args[1:] {
+			removed, err := contacts.Unblock(handle)
+			if err != nil {
+				return err
+			}
+			if len(removed) == 0 {
+				fmt.Printf("%s is not blocked\n", handle)
+				continue
+			}
+			fmt.Printf("Removed %s\n", strings.Join(removed, ", "))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown blocklist subcommand: %s", args[0])
+	}
+}
+
+// exportContacts handles `chime contacts export [--csv] [-o file] [name...]`.
+// Without names every contact is exported; without -o the contacts are written to stdout.
+// --csv, or an output file ending in .csv, writes a Google Contacts CSV instead of vCards.
+func exportContacts(args []string) error {
+	output := ""
+	asCSV := false
+	var names []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--csv" {
+			asCSV = true
+			continue
+		}
+		if args[i] == "-o" || args[i] == "--output" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a file path", args[i])
+			}
+			output = args[i+1]
+			i++
+			continue
+		}
+		names = append(names, args[i])
+	}
+
+	var toExport []contacts.Contact
+	if len(names) == 0 {
+		all, err := contacts.ListContacts()
+		if err != nil {
+			return err
+		}
+		toExport = all
+	} else {
+		for _, name := range names {
+			contact := contacts.FindContactByName(name)
+			if contact == nil {
+				return fmt.Errorf("contact not found: %s", name)
+			}
+			toExport = append(toExport, *contact)
+		}
+	}
+
+	asCSV = asCSV || strings.EqualFold(filepath.Ext(output), ".csv")
+
+	if output == "" {
+		if asCSV {
+			return contacts.WriteCSV(os.Stdout, toExport)
+		}
+		return contacts.WriteVCards(os.Stdout, toExport)
+	}
+
+	export := contacts.ExportVCardFile
+	if asCSV {
+		export = contacts.ExportCSVFile
+	}
+	if err := export(output, toExport); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d contacts to %s\n", len(toExport), output)
+	return nil
+}
+
 func printHelp() {
 	help := `Chime - Terminal iMessage Client
 
@@ -41,37 +378,113 @@ Usage:
   chime              Start the iMessage client
   chime version      Show version information
   chime help         Show this help message
+  chime contacts import-addressbook
+                     Import people from the macOS AddressBook into ~/.chime/contacts
+  chime contacts import <file.vcf>
+                     Import contacts from a vCard file
+  chime contacts import [--preset google|outlook] [--map Header=field]... [--dry-run] <file.csv>
+                     Import a CSV export, merging into matching contacts; --dry-run
+                     previews what would be created or merged
+  chime contacts export [--csv] [-o file.vcf|file.csv] [name...]
+                     Export all or the named contacts as vCard or Google CSV (stdout by default)
+  chime contacts migrate <yaml|sqlite>
+                     Move contacts between YAML files and the SQLite database
+  chime config       Show the effective configuration
+  chime config path  Show which config file is read
+  chime blocklist [list]
+                     Show blocked handles and patterns
+  chime blocklist add|remove <handle|pattern>...
+                     Block or unblock a handle, or a pattern such as "+1900*" or "*@spam.example"
 
 Navigation:
   ↑/↓ or j/k        Navigate lists
   Enter             Select/Open item
   ESC               Go back
-  q                 Quit from current view
+  q                 Quit from current view (typed as text in search and inputs)
+  ?                 Show every key binding for the current view
   ctrl+c            Force quit
 
 Menu:
   💬 Conversations  View and send messages
   👥 Contacts       Manage contacts
+  ❓ Unknown Senders Add people who message you but aren't in your contacts
+
+Unknown Senders:
+  space / A         Select handle / select all
+  a or enter        Add selected handles to contacts (tab skips one)
+  i / I             Ignore or unignore selected / show ignored handles
 
 Contacts:
   n or a            Add new contact
-  enter             Edit contact
+  enter             Show contact details and history
+  e                 Edit contact
   d                 Delete contact
+  i                 Import contacts from a vCard or CSV file
+  x / X             Export selected / all contacts as vCard or CSV
+  t                 Cycle tag filter
+  m                 Review and merge possible duplicates
+  g                 Manage contact groups
   ctrl+s            Save contact (while editing)
+  ctrl+f            Toggle favourite (while editing)
+  ctrl+n / ctrl+x   Add / remove a phone or email field (while editing)
+  ctrl+l            Cycle phone/email label (while editing)
+
+Contact Details:
+  enter             Open selected chat
+  c                 Open or start the 1:1 conversation
+  e                 Edit contact
 
 Conversations:
   /                 Search conversations
   r                 Refresh conversation list
+  tab / shift+tab   Switch between Known, Unknown Senders and All chats
+  g                 Filter by contact group
+  m                 Mute or unmute the selected chat (no unread badge)
+  b                 Block the selected chat (unblock in the blocked view)
+  B                 Show blocked chats
+
+New Conversation:
+  enter or ,        Add recipient (several recipients start a group)
+  @group            Add every member of a contact group as a broadcast
+  tab               Complete suggested contact / switch field
+  ctrl+b            Toggle broadcast to several recipients
+  ctrl+p            Toggle per-recipient personalisation
 
 Messages:
   n or c            Compose new message
   r                 Refresh messages
   ctrl+s            Send message (while composing)
+  u / e / s         Undo, edit or send now while a message is pending
+  ctrl+t            Insert template (while composing)
   ↑/↓ or j/k        Scroll messages
 
 Contact Storage:
   Contacts are stored in ~/.chime/contacts/ as YAML files
-  Each contact has a name, phone numbers, and email addresses
+  Each contact has a name, phone numbers, and email addresses, plus an optional
+  nickname (shown in conversations), organisation, birthday, tags, notes and favourite flag
+  Names are looked up in local contacts, then the macOS AddressBook; set
+  CHIME_NAME_SOURCES (e.g. addressbook,local) to change the order
+  Contact groups are stored in ~/.chime/groups.yml
+  Blocked handles and patterns are listed in ~/.chime/blocklist.yml, and
+  muted chats in ~/.chime/muted_chats.yml
+  With many contacts, run chime contacts migrate sqlite to keep them in
+  ~/.chime/contacts.db instead; it is used automatically once it exists.
+  CHIME_CONTACTS_BACKEND=yaml|sqlite forces a backend
+
+Configuration:
+  Settings are read from ~/.chime/config.yml, or $XDG_CONFIG_HOME/chime/config.yml
+  when it exists: refresh_interval, time_format (12h|24h), date_locale, default_view
+  (menu|conversations|contacts|unknown-senders), default_tab (all|known|unknown),
+  theme, data_dir, region, contacts_backend, name_sources and send (undo_delay, enter_sends,
+  verify_timeout, char_limit). Environment variables override the file.
+  theme is auto, dark, light, high-contrast, mono or a file in ~/.chime/themes/;
+  NO_COLOR forces mono.
+  keys rebinds actions, e.g. "keys: {refresh: [r, f5], quit: [ctrl+q]}"; press ? in
+  any list to see the current bindings.
+
+Templates:
+  Message templates are stored in ~/.chime/templates/ as YAML files
+  with a name and a body using {{first_name}}, {{date}}, {{time}}...
 
 Notes:
   - This app reads from your iMessage database (read-only)